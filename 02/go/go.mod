@@ -1,3 +1,7 @@
 module advent.of.code/02
 
 go 1.13
+
+require advent.of.code/intcode v0.0.0
+
+replace advent.of.code/intcode => ../../intcode/go
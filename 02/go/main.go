@@ -1,108 +1,234 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
-)
+	"sync"
 
-const (
-	opCodeAdd         = 1
-	opCodeMultiply    = 2
-	opCodeIgnore      = 99
-	sequenceSkipSteps = 3
-	partTwoMaxValue   = 99
-	partTwoOutput     = 19690720
+	"advent.of.code/intcode"
 )
 
+// valueRange represents an inclusive range of values to search, given as a
+// flag in the format `min..max`.
+type valueRange struct {
+	Min int
+	Max int
+}
+
+type nounVerb struct {
+	Noun int
+	Verb int
+}
+
+func (r *valueRange) String() string {
+	return fmt.Sprintf("%d..%d", r.Min, r.Max)
+}
+
+func (r *valueRange) Set(s string) error {
+	parts := strings.Split(s, "..")
+	if len(parts) != 2 {
+		return fmt.Errorf("expected min..max, got '%s'", s)
+	}
+
+	low, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return fmt.Errorf("invalid min '%s'", parts[0])
+	}
+
+	high, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return fmt.Errorf("invalid max '%s'", parts[1])
+	}
+
+	if low > high {
+		return fmt.Errorf("min %d is larger than max %d", low, high)
+	}
+
+	r.Min, r.Max = low, high
+
+	return nil
+}
+
 func main() {
-	if len(os.Args) < 2 {
+	var (
+		nouns   = valueRange{Min: 0, Max: 99}
+		verbs   = valueRange{Min: 0, Max: 99}
+		target  = flag.Int("target", 19690720, "the output to search for in part two")
+		workers = flag.Int("workers", runtime.NumCPU(), "number of workers searching in part two")
+		all     = flag.Bool("all", false, "report all nouns and verbs producing the target in part two instead of the lowest")
+		trace   = flag.Bool("trace", false, "write every instruction executed in part one to stderr")
+	)
+
+	flag.Var(&nouns, "nouns", "range of nouns to search in part two")
+	flag.Var(&verbs, "verbs", "range of verbs to search in part two")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
 		log.Fatal("missing file as input")
 	}
 
-	line, err := ioutil.ReadFile(os.Args[1])
+	if *workers < 1 {
+		log.Fatal("need at least one worker")
+	}
+
+	line, err := ioutil.ReadFile(flag.Arg(0))
 	if err != nil {
 		log.Fatalf("could not read file: %s", err.Error())
 	}
 
-	var (
-		stringSequence = strings.Split(string(line), ",")
-		sequence       = make([]int, len(stringSequence))
-	)
+	sequence, err := intcode.Parse(line)
+	if err != nil {
+		log.Fatalf("could not parse program: %s", err.Error())
+	}
+
+	// Noun and verb are stored at position 1 and 2.
+	if len(sequence) < 3 {
+		log.Fatalf("program is too short, only %d values", len(sequence))
+	}
+
+	var traceWriter io.Writer
+	if *trace {
+		traceWriter = os.Stderr
+	}
 
-	for i := range sequence {
-		sv := strings.TrimSpace(stringSequence[i])
-		v, _ := strconv.Atoi(sv)
-		sequence[i] = v
+	result, err := partOne(sequence, 12, 2, traceWriter)
+	if err != nil {
+		log.Fatalf("part one failed: %s", err.Error())
 	}
 
-	sequence[1] = 12
-	sequence[2] = 2
+	fmt.Println("part one:", result)
 
-	fmt.Println("part one:", partOne(sequence, 12, 2))
-	fmt.Println("part two:", partTwo(sequence))
+	matches := partTwo(sequence, *target, nouns, verbs, *workers, *all)
+	if len(matches) == 0 {
+		fmt.Printf("part two: no noun and verb produces %d\n", *target)
+	}
+
+	for _, m := range matches {
+		fmt.Printf("part two: %d (noun %d, verb %d)\n", 100*m.Noun+m.Verb, m.Noun, m.Verb)
+	}
 }
 
-func partTwo(originalSequence []int) int {
-	for i := range make([]struct{}, partTwoMaxValue) {
-		for j := range make([]struct{}, partTwoMaxValue) {
-			result := partOne(originalSequence, i, j)
-			if result == partTwoOutput {
-				v, _ := strconv.Atoi(fmt.Sprintf("%02d%02d", i, j))
-				return v
+// partTwo searches all nouns and verbs in the given ranges with a pool of
+// workers. Unless all is set no more pairs are handed out as soon as any
+// worker finds the target and only the lowest noun and verb found is
+// returned. The pairs are handed out in order and the ones already handed out
+// are still checked, so that's always the lowest pair producing the target.
+func partTwo(originalSequence []int, target int, nouns, verbs valueRange, workers int, all bool) []nounVerb {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		jobs    = make(chan nounVerb)
+		results = make(chan nounVerb)
+		matches = []nounVerb{}
+		wg      sync.WaitGroup
+	)
+
+	for range make([]struct{}, workers) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// Each worker reuses its own memory for every run.
+			sequence := make([]int, len(originalSequence))
+
+			for job := range jobs {
+				copy(sequence, originalSequence)
+
+				// Nouns and verbs making the program fail are not a match.
+				result, err := process(sequence, job.Noun, job.Verb, nil)
+				if err != nil || result != target {
+					continue
+				}
+
+				results <- job
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+
+		for noun := nouns.Min; noun <= nouns.Max; noun++ {
+			for verb := verbs.Min; verb <= verbs.Max; verb++ {
+				select {
+				case jobs <- nounVerb{Noun: noun, Verb: verb}:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for m := range results {
+		matches = append(matches, m)
+
+		if !all {
+			cancel()
+		}
 	}
 
-	return 0
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Noun != matches[j].Noun {
+			return matches[i].Noun < matches[j].Noun
+		}
+
+		return matches[i].Verb < matches[j].Verb
+	})
+
+	if !all && len(matches) > 1 {
+		matches = matches[:1]
+	}
+
+	return matches
 }
 
-func partOne(originalSequence []int, noun, verb int) int {
+func partOne(originalSequence []int, noun, verb int, trace io.Writer) (int, error) {
 	sequence := make([]int, len(originalSequence))
 	copy(sequence, originalSequence)
 
+	return process(sequence, noun, verb, trace)
+}
+
+// process runs the program in place with the given noun and verb and returns
+// the value at position 0 when it halts.
+func process(sequence []int, noun, verb int, trace io.Writer) (int, error) {
 	sequence[1] = noun
 	sequence[2] = verb
 
-	for i := 0; i < len(sequence); i++ {
-		if sequence[i] == opCodeIgnore {
-			break
-		}
-
-		if i >= len(sequence)-sequenceSkipSteps {
-			continue
-		}
+	// Only add, multiply and halt in position mode were known at the time.
+	c := intcode.Computer{
+		Sequence:         sequence,
+		FixedMemory:      true,
+		OpCodes:          []int{intcode.OpCodeAdd, intcode.OpCodeMultiply, intcode.OpCodeHalt},
+		PositionModeOnly: true,
+		Trace:            trace,
+	}
 
-		var (
-			opCode = sequence[i]
-			n1Pos  = sequence[i+1]
-			n2Pos  = sequence[i+2]
-			pos    = sequence[i+3]
-			n1     = sequence[n1Pos]
-			n2     = sequence[n2Pos]
-		)
-
-		// Invalid sequence.
-		if pos >= len(sequence) {
-			return 0
-		}
+	if err := c.Process(); err != nil {
+		return 0, err
+	}
 
-		switch opCode {
-		case opCodeAdd:
-			sequence[pos] = n1 + n2
-			i += sequenceSkipSteps
-		case opCodeMultiply:
-			sequence[pos] = n1 * n2
-			i += sequenceSkipSteps
-		case opCodeIgnore:
-			continue
-		default:
-			panic("unknown instruction")
-		}
+	// There's no input to the program so it's only stopped without an error
+	// if it halted or is waiting for input.
+	if !c.Halted {
+		return 0, fmt.Errorf("program waiting for input at position %d", c.Pointer)
 	}
 
-	return sequence[0]
+	return sequence[0], nil
 }
@@ -1,3 +1,7 @@
 module advent.of.code/3
 
 go 1.13
+
+require advent.of.code/grid v0.0.0
+
+replace advent.of.code/grid => ../../grid/go
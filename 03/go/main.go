@@ -1,21 +1,19 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
-	"math"
-	"os"
+	"sort"
 	"strconv"
 	"strings"
-)
 
-type coordinates struct {
-	x int
-	y int
-}
+	"advent.of.code/grid"
+)
 
-type coordinateMap map[coordinates]int
+type coordinateMap map[grid.Point]int
 
 // Directions
 const (
@@ -25,119 +23,269 @@ const (
 	Right = "R"
 )
 
+// nolint: gochecknoglobals
+var headings = map[string]grid.Heading{
+	Up:    grid.Up,
+	Down:  grid.Down,
+	Left:  grid.Left,
+	Right: grid.Right,
+}
+
+// move represents a single step in the input, moving the wire length points in
+// one direction.
+type move struct {
+	Heading grid.Heading
+	Length  int
+}
+
 func main() {
-	if len(os.Args) < 2 {
+	var (
+		method = flag.String("method", "segments", "how to find intersections: segments or cells")
+		steps  = flag.String("steps", "first", "how to count steps to points visited more than once: first or cumulative")
+	)
+
+	flag.Parse()
+
+	if flag.NArg() < 1 {
 		log.Fatal("missing file as input")
 	}
 
-	fileContent, err := ioutil.ReadFile(os.Args[1])
+	fileContent, err := ioutil.ReadFile(flag.Arg(0))
 	if err != nil {
 		log.Fatalf("could not read file: %s", err.Error())
 	}
 
 	var (
-		fileLines        = strings.Split(string(fileContent), "\n")
-		r1               = strings.Split(fileLines[0], ",")
-		r2               = strings.Split(fileLines[1], ",")
-		distances        []int
-		shortestDistance int
-		fewestSteps      int
+		wires      = [][]move{}
+		finder     func(a, b []move) []crossing
+		cumulative bool
 	)
 
-	m1 := mark(r1)
-	m2 := mark(r2)
+	for _, line := range strings.Split(string(fileContent), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
 
-	bothFunc := func(ma, mb coordinateMap) coordinateMap {
-		both := coordinateMap{}
+		wire, err := parseWire(line)
+		if err != nil {
+			log.Fatalf("could not parse wire %d: %s", len(wires)+1, err.Error())
+		}
+
+		wires = append(wires, wire)
+	}
+
+	if len(wires) < 2 {
+		log.Fatal("need at least two wires")
+	}
+
+	switch *steps {
+	case "first":
+		cumulative = false
+	case "cumulative":
+		cumulative = true
+	default:
+		log.Fatalf("unknown step counting '%s'", *steps)
+	}
 
-		for k := range ma {
-			if _, ok := mb[k]; ok {
-				both[k] = ma[k] + mb[k]
+	switch *method {
+	case "segments":
+		finder = func(a, b []move) []crossing {
+			return crossings(segments(a), segments(b), cumulative)
+		}
+	case "cells":
+		finder = func(a, b []move) []crossing {
+			return cellCrossings(a, b, cumulative)
+		}
+	default:
+		log.Fatalf("unknown method '%s'", *method)
+	}
+
+	// Keep the original output for the puzzle with two wires.
+	if len(wires) == 2 {
+		shortestDistance, fewestSteps := closest(finder(wires[0], wires[1]))
+
+		fmt.Println("shortest distance", shortestDistance)
+		fmt.Println("fewest steps", fewestSteps)
+
+		return
+	}
+
+	// The wires crossing each point, numbered from one as the lines in the
+	// input.
+	crossedBy := map[grid.Point]map[int]struct{}{}
+
+	for i := range wires {
+		for j := i + 1; j < len(wires); j++ {
+			found := finder(wires[i], wires[j])
+
+			if len(found) == 0 {
+				fmt.Printf("wires %d and %d: no crossings\n", i+1, j+1)
+				continue
+			}
+
+			shortestDistance, fewestSteps := closest(found)
+
+			fmt.Printf(
+				"wires %d and %d: shortest distance %d, fewest steps %d\n",
+				i+1, j+1, shortestDistance, fewestSteps,
+			)
+
+			for _, c := range found {
+				if _, ok := crossedBy[c.Point]; !ok {
+					crossedBy[c.Point] = map[int]struct{}{}
+				}
+
+				crossedBy[c.Point][i+1] = struct{}{}
+				crossedBy[c.Point][j+1] = struct{}{}
 			}
 		}
+	}
+
+	showCrossedByMany(crossedBy, 3)
+}
 
-		return both
+// showCrossedByMany prints every point crossed by at least the given number of
+// wires, closest to the central port first.
+func showCrossedByMany(crossedBy map[grid.Point]map[int]struct{}, atLeast int) {
+	points := []grid.Point{}
+
+	for p, wires := range crossedBy {
+		if len(wires) >= atLeast {
+			points = append(points, p)
+		}
 	}
 
-	intersections := bothFunc(m1, m2)
+	sort.Slice(points, func(i, j int) bool {
+		di, dj := points[i].Manhattan(), points[j].Manhattan()
+		if di != dj {
+			return di < dj
+		}
+
+		if points[i].Y != points[j].Y {
+			return points[i].Y < points[j].Y
+		}
+
+		return points[i].X < points[j].X
+	})
 
-	for c := range intersections {
-		distances = append(distances, manhattanDistance(c))
+	fmt.Printf("points crossed by %d or more wires: %d\n", atLeast, len(points))
 
-		totalSteps := intersections[c]
+	for _, p := range points {
+		wires := []string{}
 
-		if fewestSteps == 0 || totalSteps < fewestSteps {
-			fewestSteps = totalSteps
+		for w := range crossedBy[p] {
+			wires = append(wires, strconv.Itoa(w))
 		}
+
+		sort.Slice(wires, func(i, j int) bool {
+			a, _ := strconv.Atoi(wires[i])
+			b, _ := strconv.Atoi(wires[j])
+
+			return a < b
+		})
+
+		fmt.Printf("  %d,%d (distance %d): wires %s\n", p.X, p.Y, p.Manhattan(), strings.Join(wires, ", "))
 	}
+}
 
-	for _, d := range distances {
-		if shortestDistance == 0 || d < shortestDistance {
+// closest returns the Manhattan distance to the crossing closest to the
+// central port and the fewest combined steps to any crossing.
+func closest(found []crossing) (int, int) {
+	var shortestDistance, fewestSteps int
+
+	for _, c := range found {
+		if d := c.Point.Manhattan(); shortestDistance == 0 || d < shortestDistance {
 			shortestDistance = d
 		}
+
+		if fewestSteps == 0 || c.Steps < fewestSteps {
+			fewestSteps = c.Steps
+		}
 	}
 
-	fmt.Println("shortest distance", shortestDistance)
-	fmt.Println("fewest steps", fewestSteps)
+	return shortestDistance, fewestSteps
 }
 
-func mark(steps []string) map[coordinates]int {
-	type updateCoordianteFunc func(x, y int) (int, int)
-
+// cellCrossings finds the crossings by marking every point visited by each
+// wire. The central port doesn't count even if both wires pass it again.
+func cellCrossings(r1, r2 []move, cumulative bool) []crossing {
 	var (
-		x, y          = 0, 0
-		totalSteps    = 0
-		c             = coordinateMap{}
-		updateFuncMap = map[string]updateCoordianteFunc{
-			Up: func(x, y int) (int, int) {
-				y--
-				return x, y
-			},
-			Down: func(x, y int) (int, int) {
-				y++
-				return x, y
-			},
-			Left: func(x, y int) (int, int) {
-				x--
-				return x, y
-			},
-			Right: func(x, y int) (int, int) {
-				x++
-				return x, y
-			},
-		}
+		m1    = mark(r1, cumulative)
+		m2    = mark(r2, cumulative)
+		found = []crossing{}
 	)
 
-	for _, step := range steps {
-		direction, length := getDirectionAndLength(step)
+	for k := range m1 {
+		if k == (grid.Point{}) {
+			continue
+		}
+
+		if _, ok := m2[k]; ok {
+			found = append(found, crossing{Point: k, Steps: m1[k] + m2[k]})
+		}
+	}
 
-		for range make([]struct{}, length) {
+	return found
+}
+
+// mark returns the steps taken by the wire to reach every point it visits. A
+// point visited more than once keeps the steps of the first visit, or the sum
+// of every visit if cumulative is set.
+func mark(moves []move, cumulative bool) coordinateMap {
+	var (
+		position   = grid.Point{}
+		totalSteps = 0
+		c          = coordinateMap{}
+	)
+
+	for _, m := range moves {
+		for range make([]struct{}, m.Length) {
 			totalSteps++
 
-			x, y = updateFuncMap[direction](x, y)
+			position = position.Move(m.Heading, 1)
 
-			co := coordinates{x, y}
-			if _, ok := c[co]; !ok {
-				c[co] = 0
+			if _, ok := c[position]; !ok || cumulative {
+				c[position] += totalSteps
 			}
-
-			c[co] += totalSteps
 		}
 	}
 
 	return c
 }
 
-func getDirectionAndLength(step string) (string, int) {
-	var (
-		direction = string(step[0])
-		strLength = step[1:]
-		length, _ = strconv.Atoi(strLength)
-	)
+// parseWire returns the moves of a wire given as comma separated steps like
+// R75,D30.
+func parseWire(line string) ([]move, error) {
+	moves := []move{}
+
+	for _, step := range strings.Split(line, ",") {
+		m, err := parseMove(strings.TrimSpace(step))
+		if err != nil {
+			return nil, err
+		}
+
+		moves = append(moves, m)
+	}
 
-	return direction, length
+	return moves, nil
 }
 
-func manhattanDistance(c coordinates) int {
-	return int(math.Abs(0-float64(c.x)) + math.Abs(0-float64(c.y)))
+// parseMove returns the move for a single step, a direction followed by the
+// number of points to move.
+func parseMove(step string) (move, error) {
+	if step == "" {
+		return move{}, errors.New("empty step")
+	}
+
+	heading, ok := headings[step[:1]]
+	if !ok {
+		return move{}, fmt.Errorf("unknown direction '%s' in step '%s'", step[:1], step)
+	}
+
+	length, err := strconv.Atoi(step[1:])
+	if err != nil || length < 0 {
+		return move{}, fmt.Errorf("invalid length in step '%s'", step)
+	}
+
+	return move{Heading: heading, Length: length}, nil
 }
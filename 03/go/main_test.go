@@ -0,0 +1,233 @@
+package main
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"advent.of.code/grid"
+)
+
+// readWires returns the wires in the file.
+func readWires(tb testing.TB, filename string) [][]move {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		tb.Fatalf("could not read file: %s", err.Error())
+	}
+
+	wires := [][]move{}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		wire, err := parseWire(line)
+		if err != nil {
+			tb.Fatalf("could not parse wire: %s", err.Error())
+		}
+
+		wires = append(wires, wire)
+	}
+
+	return wires
+}
+
+// randomWire returns a wire with the given number of steps, each moving at
+// most maxLength points in a random direction.
+func randomWire(rnd *rand.Rand, steps, maxLength int) []move {
+	var (
+		letters = []string{Up, Down, Left, Right}
+		wire    = make([]move, steps)
+	)
+
+	for i := range wire {
+		wire[i] = move{
+			Heading: headings[letters[rnd.Intn(len(letters))]],
+			Length:  rnd.Intn(maxLength) + 1,
+		}
+	}
+
+	return wire
+}
+
+// benchmarkInput is a pair of wires to find the crossings of.
+type benchmarkInput struct {
+	Name  string
+	Wires [][]move
+}
+
+// benchmarkInputs returns the puzzle input and two long generated wires.
+func benchmarkInputs(b *testing.B) []benchmarkInput {
+	rnd := rand.New(rand.NewSource(1))
+
+	return []benchmarkInput{
+		{Name: "input", Wires: readWires(b, "../input")},
+		{Name: "long", Wires: [][]move{randomWire(rnd, 300, 2000), randomWire(rnd, 300, 2000)}},
+	}
+}
+
+func BenchmarkCrossingsSegments(b *testing.B) {
+	for _, in := range benchmarkInputs(b) {
+		wires := in.Wires
+
+		b.Run(in.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				crossings(segments(wires[0]), segments(wires[1]), false)
+			}
+		})
+	}
+}
+
+func BenchmarkCrossingsCells(b *testing.B) {
+	for _, in := range benchmarkInputs(b) {
+		wires := in.Wires
+
+		b.Run(in.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				cellCrossings(wires[0], wires[1], false)
+			}
+		})
+	}
+}
+
+func TestClosest(t *testing.T) {
+	cases := []struct {
+		a, b       string
+		cumulative bool
+		distance   int
+		steps      int
+	}{
+		{a: "R8,U5,L5,D3", b: "U7,R6,D4,L4", distance: 6, steps: 30},
+		{a: "R8,U5,L5,D3", b: "U7,R6,D4,L4", cumulative: true, distance: 6, steps: 30},
+		{
+			a:        "R75,D30,R83,U83,L12,D49,R71,U7,L72",
+			b:        "U62,R66,U55,R34,D71,R55,D58,R83",
+			distance: 159, steps: 610,
+		},
+		{
+			a:        "R98,U47,R26,D63,R33,U87,L62,D20,R33,U53,R51",
+			b:        "U98,R91,D20,R16,D67,R40,U7,R15,U6,R7",
+			distance: 135, steps: 410,
+		},
+
+		// The first wire doubles back over 7,0 where the second wire
+		// crosses it, first after 7 steps and again after 13 steps.
+		{a: "R10,L5,U3", b: "U1,R7,D2", distance: 6, steps: 16},
+		{a: "R10,L5,U3", b: "U1,R7,D2", cumulative: true, distance: 6, steps: 22},
+
+		// The second wire crosses itself at 4,-2, which is on the first
+		// wire, after 6 and 12 steps before following the first wire back
+		// down to 4,0.
+		{a: "R4,U4", b: "U2,R6,U1,L2,D3", distance: 4, steps: 12},
+		{a: "R4,U4", b: "U2,R6,U1,L2,D3", cumulative: true, distance: 4, steps: 18},
+	}
+
+	for _, tc := range cases {
+		var (
+			a = mustParseWire(t, tc.a)
+			b = mustParseWire(t, tc.b)
+		)
+
+		methods := map[string][]crossing{
+			"segments": crossings(segments(a), segments(b), tc.cumulative),
+			"cells":    cellCrossings(a, b, tc.cumulative),
+		}
+
+		for method, found := range methods {
+			distance, steps := closest(found)
+
+			if distance != tc.distance || steps != tc.steps {
+				t.Errorf(
+					"%s and %s with %s (cumulative %t): got distance %d and %d steps, want %d and %d",
+					tc.a, tc.b, method, tc.cumulative, distance, steps, tc.distance, tc.steps,
+				)
+			}
+		}
+	}
+}
+
+func TestSelfCrossingOnly(t *testing.T) {
+	var (
+		a = mustParseWire(t, "R5,U2,L2,D4")
+		b = mustParseWire(t, "D1,R1")
+	)
+
+	if found := crossings(segments(a), segments(b), false); len(found) != 0 {
+		t.Errorf("segments: got crossings %v, a wire crossing itself isn't a crossing", found)
+	}
+
+	if found := cellCrossings(a, b, false); len(found) != 0 {
+		t.Errorf("cells: got crossings %v, a wire crossing itself isn't a crossing", found)
+	}
+}
+
+func TestParseMove(t *testing.T) {
+	valid := map[string]move{
+		"U7":  {Heading: headings[Up], Length: 7},
+		"D0":  {Heading: headings[Down], Length: 0},
+		"L12": {Heading: headings[Left], Length: 12},
+		"R3":  {Heading: headings[Right], Length: 3},
+	}
+
+	for step, want := range valid {
+		got, err := parseMove(step)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", step, err)
+			continue
+		}
+
+		if got != want {
+			t.Errorf("%s: got %v, want %v", step, got, want)
+		}
+	}
+
+	for _, step := range []string{"", "X5", "u5", "5", "U", "U-2", "Uabc", "U1.5", "UU1"} {
+		if _, err := parseMove(step); err == nil {
+			t.Errorf("'%s': expected an error", step)
+		}
+	}
+
+	if _, err := parseWire("R8,U5,,D3"); err == nil {
+		t.Error("expected an error for an empty step in a wire")
+	}
+}
+
+// TestSegmentsMatchCells checks that sweeping segments finds the same crossings
+// with the same steps as marking cells for random short wires, which cross
+// themselves and overlap each other a lot.
+func TestSegmentsMatchCells(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		var (
+			a          = randomWire(rnd, rnd.Intn(8)+1, 6)
+			b          = randomWire(rnd, rnd.Intn(8)+1, 6)
+			cumulative = i%2 == 1
+			want       = crossingSteps(cellCrossings(a, b, cumulative))
+			got        = crossingSteps(crossings(segments(a), segments(b), cumulative))
+		)
+
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%v and %v (cumulative %t): segments found %v, cells found %v", a, b, cumulative, got, want)
+		}
+	}
+}
+
+// crossingSteps returns the steps to every crossing by point.
+func crossingSteps(found []crossing) map[grid.Point]int {
+	steps := map[grid.Point]int{}
+
+	for _, c := range found {
+		steps[c.Point] = c.Steps
+	}
+
+	return steps
+}
+
+func mustParseWire(t *testing.T, line string) []move {
+	wire, err := parseWire(line)
+	if err != nil {
+		t.Fatalf("could not parse wire %s: %s", line, err.Error())
+	}
+
+	return wire
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"sort"
+
+	"advent.of.code/grid"
+)
+
+// segment represents a straight part of a wire from Start to End, both
+// included. Steps is the number of steps taken by the wire to reach Start.
+type segment struct {
+	Start   grid.Point
+	End     grid.Point
+	Heading grid.Heading
+	Steps   int
+}
+
+// crossing represents a point where two wires cross and the combined number of
+// steps taken by both wires to get there.
+type crossing struct {
+	Point grid.Point
+	Steps int
+}
+
+// segments returns every segment of the wire, starting at the central port.
+func segments(moves []move) []segment {
+	var (
+		position   = grid.Point{}
+		totalSteps = 0
+		result     = []segment{}
+	)
+
+	for _, m := range moves {
+		s := segment{
+			Start:   position,
+			End:     position.Move(m.Heading, m.Length),
+			Heading: m.Heading,
+			Steps:   totalSteps,
+		}
+
+		result = append(result, s)
+
+		position = s.End
+		totalSteps += m.Length
+	}
+
+	return result
+}
+
+func (s segment) horizontal() bool {
+	return s.Heading.Y == 0
+}
+
+// stepsTo returns the number of steps taken by the wire to reach p on the
+// segment.
+func (s segment) stepsTo(p grid.Point) int {
+	return s.Steps + grid.Manhattan(s.Start, p)
+}
+
+// contains returns true if p is on the segment.
+func (s segment) contains(p grid.Point) bool {
+	from, to := s.span()
+
+	if s.horizontal() {
+		return p.Y == s.Start.Y && p.X >= from && p.X <= to
+	}
+
+	return p.X == s.Start.X && p.Y >= from && p.Y <= to
+}
+
+// span returns the smallest and largest value along the segment.
+func (s segment) span() (int, int) {
+	if s.horizontal() {
+		return minMax(s.Start.X, s.End.X)
+	}
+
+	return minMax(s.Start.Y, s.End.Y)
+}
+
+// line returns the constant coordinate of the segment, Y for horizontal
+// segments and X for vertical segments.
+func (s segment) line() int {
+	if s.horizontal() {
+		return s.Start.Y
+	}
+
+	return s.Start.X
+}
+
+// point returns the point on the segment at the given value along it.
+func (s segment) point(v int) grid.Point {
+	if s.horizontal() {
+		return grid.Point{X: v, Y: s.Start.Y}
+	}
+
+	return grid.Point{X: s.Start.X, Y: v}
+}
+
+// crossings returns every point where the wires cross, except the central
+// port. Perpendicular segments are found by sweeping over X with the
+// horizontal segments active while the sweep is within them. Parallel segments
+// on the same line are checked for overlap separately.
+func crossings(a, b []segment, cumulative bool) []crossing {
+	var (
+		points = map[grid.Point]struct{}{}
+		result = []crossing{}
+	)
+
+	for _, c := range sweep(a, b) {
+		points[c.Point] = struct{}{}
+	}
+
+	for _, c := range sweep(b, a) {
+		points[c.Point] = struct{}{}
+	}
+
+	for _, pair := range overlapping(a, b) {
+		for _, p := range overlapPoints(pair[0], pair[1]) {
+			points[p] = struct{}{}
+		}
+	}
+
+	delete(points, grid.Point{})
+
+	for p := range points {
+		result = append(result, crossing{Point: p, Steps: wireSteps(a, p, cumulative) + wireSteps(b, p, cumulative)})
+	}
+
+	return result
+}
+
+// wireSteps returns the steps taken by the wire to reach p the first time, or
+// the sum of the steps for every visit if cumulative is set. The start of a
+// segment is left out since it's the end of the previous one.
+func wireSteps(wire []segment, p grid.Point, cumulative bool) int {
+	total := 0
+
+	for _, s := range wire {
+		if p == s.Start || !s.contains(p) {
+			continue
+		}
+
+		if !cumulative {
+			return s.stepsTo(p)
+		}
+
+		total += s.stepsTo(p)
+	}
+
+	return total
+}
+
+// perpendicular represents a horizontal segment crossing a vertical segment.
+type perpendicular struct {
+	Horizontal segment
+	Vertical   segment
+	Point      grid.Point
+}
+
+// sweep returns every point where a horizontal segment from a crosses a
+// vertical segment from b.
+func sweep(a, b []segment) []perpendicular {
+	const (
+		eventAdd = iota
+		eventQuery
+		eventRemove
+	)
+
+	type event struct {
+		X       int
+		Kind    int
+		Segment segment
+	}
+
+	var (
+		events = []event{}
+		active = []segment{}
+		result = []perpendicular{}
+	)
+
+	for _, s := range a {
+		if !s.horizontal() {
+			continue
+		}
+
+		from, to := s.span()
+		events = append(events, event{X: from, Kind: eventAdd, Segment: s}, event{X: to, Kind: eventRemove, Segment: s})
+	}
+
+	for _, s := range b {
+		if s.horizontal() {
+			continue
+		}
+
+		events = append(events, event{X: s.Start.X, Kind: eventQuery, Segment: s})
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].X != events[j].X {
+			return events[i].X < events[j].X
+		}
+
+		return events[i].Kind < events[j].Kind
+	})
+
+	// The active segments are kept sorted by Y so every segment crossed by a
+	// vertical segment can be found with a binary search.
+	byY := func(y int) int {
+		return sort.Search(len(active), func(i int) bool { return active[i].Start.Y >= y })
+	}
+
+	for _, e := range events {
+		switch e.Kind {
+		case eventAdd:
+			i := byY(e.Segment.Start.Y)
+			active = append(active, segment{})
+			copy(active[i+1:], active[i:])
+			active[i] = e.Segment
+
+		case eventRemove:
+			for i := byY(e.Segment.Start.Y); i < len(active); i++ {
+				if active[i] == e.Segment {
+					active = append(active[:i], active[i+1:]...)
+					break
+				}
+			}
+
+		case eventQuery:
+			from, to := e.Segment.span()
+
+			for i := byY(from); i < len(active) && active[i].Start.Y <= to; i++ {
+				result = append(result, perpendicular{
+					Horizontal: active[i],
+					Vertical:   e.Segment,
+					Point:      grid.Point{X: e.Segment.Start.X, Y: active[i].Start.Y},
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// overlapping returns every pair of parallel segments on the same line that
+// overlap, with the segment from a first.
+func overlapping(a, b []segment) [][2]segment {
+	type key struct {
+		Horizontal bool
+		Line       int
+	}
+
+	var (
+		lines  = map[key][]segment{}
+		result = [][2]segment{}
+	)
+
+	for _, s := range b {
+		k := key{Horizontal: s.horizontal(), Line: s.line()}
+		lines[k] = append(lines[k], s)
+	}
+
+	for _, sa := range a {
+		fromA, toA := sa.span()
+
+		for _, sb := range lines[key{Horizontal: sa.horizontal(), Line: sa.line()}] {
+			fromB, toB := sb.span()
+
+			if fromA <= toB && fromB <= toA {
+				result = append(result, [2]segment{sa, sb})
+			}
+		}
+	}
+
+	return result
+}
+
+// overlapPoints returns every point shared by two parallel segments on the
+// same line.
+func overlapPoints(a, b segment) []grid.Point {
+	var (
+		fromA, toA = a.span()
+		fromB, toB = b.span()
+		points     = []grid.Point{}
+	)
+
+	for v := max(fromA, fromB); v <= min(toA, toB); v++ {
+		points = append(points, a.point(v))
+	}
+
+	return points
+}
+
+func minMax(a, b int) (int, int) {
+	if a < b {
+		return a, b
+	}
+
+	return b, a
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
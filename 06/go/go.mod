@@ -1,3 +1,10 @@
 module advent.of.code/6
 
 go 1.13
+
+require advent.of.code/pathfind v0.0.0
+
+replace (
+	advent.of.code/grid => ../../grid/go
+	advent.of.code/pathfind => ../../pathfind/go
+)
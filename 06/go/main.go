@@ -6,15 +6,15 @@ import (
 	"log"
 	"os"
 	"strings"
+
+	"advent.of.code/pathfind"
 )
 
 func main() {
 	orbits := readFile()
 
 	f, b := parseMap(orbits)
-	d := map[string]int{}
-
-	findDistanceFrom("COM", 0, f, d)
+	d := pathfind.BFS(f, "COM").Dist
 
 	totalOrbits := 0
 	for _, distance := range d {
@@ -24,66 +24,45 @@ func main() {
 	// Part 1 solution
 	fmt.Println("Total orbis", totalOrbits)
 
+	// Moving between orbits is the same as moving between the objects orbited
+	// by 'YOU' and 'SAN' in either direction.
 	var (
-		youBackrefs           = backrefsFor("YOU", b, map[string]struct{}{})
-		sanBackrefs           = backrefsFor("SAN", b, map[string]struct{}{})
-		commonKeys            = []string{}
-		nearestSharedDistance = 0
+		src, srcOK = b["YOU"]
+		dst, dstOK = b["SAN"]
 	)
 
-	// Check common keys found as backrefs from both 'YOU' and 'SAN'.
-	for k := range youBackrefs {
-		if _, ok := sanBackrefs[k]; ok {
-			commonKeys = append(commonKeys, k)
-		}
-	}
-
-	// Iterate over the common ones and find the distance to the one closest to
-	// you/farthest 'COM'.
-	for _, k := range commonKeys {
-		if nearestSharedDistance == 0 || d[k] > nearestSharedDistance {
-			nearestSharedDistance = d[k]
-		}
+	if !srcOK || !dstOK {
+		fmt.Println("Both 'YOU' and 'SAN' must orbit something")
+		return
 	}
 
-	// Add the distance between 'YOU' and the closest shared + the distance
-	// between 'SAN' and the closest shared one to get a delta.
-	srcMinusNearest := len(youBackrefs) - nearestSharedDistance
-	dstMinusNearest := len(sanBackrefs) - nearestSharedDistance
-	delta := srcMinusNearest + dstMinusNearest - 2 // Remove 'YOU' and 'SAN'.
-
-	// Part 2 solution
-	fmt.Println("Minimum orbits to move between 'YOU' and 'SAN':", delta)
-}
-
-func backrefsFor(planet string, backrefs map[string]string, m map[string]struct{}) map[string]struct{} {
-	backref, ok := backrefs[planet]
+	path, ok := pathfind.BFS(undirected(f), src).Path(dst)
 	if !ok {
-		return m
+		fmt.Println("No path between 'YOU' and 'SAN'")
+		return
 	}
 
-	m[backref] = struct{}{}
-
-	return backrefsFor(backref, backrefs, m)
+	// Part 2 solution
+	fmt.Println("Minimum orbits to move between 'YOU' and 'SAN':", len(path)-1)
 }
 
-func findDistanceFrom(planet string, distance int, orbits map[string][]string, store map[string]int) {
-	store[planet] = distance
-	nextDistance := distance + 1
+// undirected returns a graph where every object can be reached from the one
+// it orbits and the other way around.
+func undirected(orbits pathfind.Explicit) pathfind.Explicit {
+	g := pathfind.Explicit{}
 
-	f, ok := orbits[planet]
-	if !ok {
-		return
+	for from, edges := range orbits {
+		for _, e := range edges {
+			g.AddUndirected(from, e.To, 1)
+		}
 	}
 
-	for _, p := range f {
-		findDistanceFrom(p, nextDistance, orbits, store)
-	}
+	return g
 }
 
-func parseMap(orbits []string) (map[string][]string, map[string]string) {
+func parseMap(orbits []string) (pathfind.Explicit, map[string]string) {
 	var (
-		forward  = map[string][]string{}
+		forward  = pathfind.Explicit{}
 		backward = map[string]string{}
 	)
 
@@ -91,7 +70,7 @@ func parseMap(orbits []string) (map[string][]string, map[string]string) {
 		parts := strings.Split(line, ")")
 		lhs, rhs := parts[0], parts[1]
 
-		forward[lhs] = append(forward[lhs], rhs)
+		forward.Add(lhs, rhs, 1)
 		backward[rhs] = lhs
 	}
 
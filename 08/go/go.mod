@@ -1,3 +1,7 @@
 module advent.of.code/8
 
 go 1.13
+
+require advent.of.code/ocr v0.0.0
+
+replace advent.of.code/ocr => ../../ocr/go
@@ -7,6 +7,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"advent.of.code/ocr"
 )
 
 const (
@@ -79,6 +81,21 @@ func partTwo(layers []Layer) {
 
 	fmt.Println("rendering the image:")
 	printLayerLetter(layerToDraw)
+
+	lit := make([][]bool, len(layerToDraw))
+
+	for i, row := range layerToDraw {
+		for _, col := range row {
+			lit[i] = append(lit[i], col == colorWhite)
+		}
+	}
+
+	text, err := ocr.Recognize(lit)
+	if err != nil {
+		log.Printf("could not read all letters: %s", err.Error())
+	}
+
+	fmt.Println("part two:", text)
 }
 
 func createLayers(line []byte) []Layer {
@@ -1,3 +1,15 @@
 module advent.of.code/11
 
 go 1.13
+
+require (
+	advent.of.code/grid v0.0.0
+	advent.of.code/intcode v0.0.0
+	advent.of.code/ocr v0.0.0
+)
+
+replace (
+	advent.of.code/grid => ../../grid/go
+	advent.of.code/intcode => ../../intcode/go
+	advent.of.code/ocr => ../../ocr/go
+)
@@ -1,333 +1,254 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io/ioutil"
+	"log"
 	"os"
-	"strconv"
-	"strings"
-)
 
-// * ParamModePosition  -> Use the value found at slice[n]
-// * ParamModeImmediate -> Use the value n
-// * ParamModeRelative  -> Use the value found at slice[BASE+n]
-const (
-	paramModePosition = iota
-	paramModeImmediate
-	paramModeRelative
+	"advent.of.code/grid"
+	"advent.of.code/intcode"
+	"advent.of.code/ocr"
 )
 
-const (
-	opCodeAdd         = 1
-	opCodeMultiply    = 2
-	opCodeStore       = 3
-	opCodeOutput      = 4
-	opCodeJumpIfTrue  = 5
-	opCodeJumpIfFalse = 6
-	opCodeLessThan    = 7
-	opCodeEquals      = 8
-	opCodeAdjustBase  = 9
-	opCodeHalt        = 99
-)
+// color represents the color of a panel, using the same values as the
+// robot program.
+type color int
 
 const (
-	black = "█"
-	white = "░"
+	colorBlack color = iota
+	colorWhite
 )
 
-// nolint: gochecknoglobals
-var jumpMap = map[int]int{
-	opCodeAdd:         4,
-	opCodeMultiply:    4,
-	opCodeStore:       2,
-	opCodeOutput:      2,
-	opCodeJumpIfTrue:  3,
-	opCodeJumpIfFalse: 3,
-	opCodeLessThan:    4,
-	opCodeEquals:      4,
-	opCodeAdjustBase:  2,
-}
+func (c color) String() string {
+	switch c {
+	case colorBlack:
+		return "█"
+	case colorWhite:
+		return "░"
+	}
 
-type computer struct {
-	Input         int
-	Output        []int
-	Pointer       int
-	Base          int
-	Sequence      []int
-	Halted        bool
-	PauseAtOutput bool
+	return "?"
 }
 
-type direction int
-
+// Turn codes given by the robot program. Only left and right are used by the
+// puzzle, turning around and keeping the heading are used by custom programs.
 const (
-	directionUp = iota
-	directionLeft
-	directionDown
-	directionRight
+	turnLeft = iota
+	turnRight
+	turnAround
+	turnNone
 )
 
+// nolint: gochecknoglobals
+var turnMap = map[int]func(grid.Heading) grid.Heading{
+	turnLeft:   grid.Heading.RotateLeft,
+	turnRight:  grid.Heading.RotateRight,
+	turnAround: grid.Heading.Reverse,
+	turnNone:   func(h grid.Heading) grid.Heading { return h },
+}
+
+// robot represents the painting robot. The grid holds the color of every
+// panel painted.
 type robot struct {
-	X         int
-	Y         int
-	Direction direction
-	Seen      map[string]struct{}
-	Grid      [][]string
-	Computer  computer
+	Position grid.Point
+	Heading  grid.Heading
+	Seen     map[grid.Point]struct{}
+	Grid     grid.Grid
+	History  []paintStep
 }
 
 func main() {
 	var (
-		line, _        = ioutil.ReadFile(os.Args[1])
-		stringSequence = strings.Split(string(line), ",")
-		sequence       = make([]int, len(stringSequence))
+		pngFile  = flag.String("png", "", "write the hull painted in part two as PNG to this file")
+		svgFile  = flag.String("svg", "", "write the hull painted in part two as SVG to this file")
+		gifFile  = flag.String("gif", "", "write every paint step in part two as an animated GIF to this file")
+		gifDelay = flag.Int("gif-delay", 5, "delay between GIF frames in 100ths of a second")
+		cellSize = flag.Int("cell-size", 10, "size in pixels of each panel when exporting")
+		black    = flag.String("black", "#000000", "color of black panels when exporting")
+		white    = flag.String("white", "#ffffff", "color of white panels when exporting")
 	)
 
-	for i := range sequence {
-		sequence[i], _ = strconv.Atoi(strings.TrimSpace(stringSequence[i]))
-	}
-
-	r1 := newRobot(sequence)
-	r1.run(1)
-	fmt.Println("number of panes painted at least once:", len(r1.Seen))
+	flag.Parse()
 
-	r2 := newRobot(sequence)
-	r2.run(2)
-	r2.show()
-}
-
-func newRobot(sequence []int) *robot {
-	var (
-		size = 90
-		grid = make([][]string, size)
-	)
+	if flag.NArg() < 1 {
+		log.Fatal("missing file as input")
+	}
 
-	r := robot{
-		X:         75,
-		Y:         40,
-		Direction: directionUp,
-		Seen:      map[string]struct{}{},
-		Grid:      grid,
-		Computer: computer{
-			Sequence:      make([]int, len(sequence)),
-			PauseAtOutput: true,
-		},
+	if *cellSize < 1 {
+		log.Fatal("cell size must be at least 1")
 	}
 
-	copy(r.Computer.Sequence, sequence)
+	p := palette{CellSize: *cellSize}
 
-	for i := range r.Grid {
-		r.Grid[i] = make([]string, size)
+	var err error
 
-		for j := range r.Grid[i] {
-			r.Grid[i][j] = "."
-		}
+	if p.Black, err = parseHexColor(*black); err != nil {
+		log.Fatal(err)
 	}
 
-	return &r
-}
+	if p.White, err = parseHexColor(*white); err != nil {
+		log.Fatal(err)
+	}
 
-func (r *robot) run(part int) {
-	for {
-		// Part two starts at white square
-		if part == 2 && r.Computer.Pointer == 0 {
-			r.Grid[r.X][r.Y] = white
-		}
+	line, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("could not read file: %s", err.Error())
+	}
 
-		// All is black by default, only change input if painted white.
-		if r.Grid[r.X][r.Y] == white {
-			r.Computer.Input = 1
-		} else {
-			r.Computer.Input = 0
-		}
+	sequence, err := intcode.Parse(line)
+	if err != nil {
+		log.Fatalf("could not parse program: %s", err.Error())
+	}
 
-		// Fetch two codes
-		r.Computer.process()
-		r.Computer.process()
+	r1 := newRobot(colorBlack)
+	if err := intcode.Run(intcode.New(sequence), r1); err != nil {
+		log.Fatal(err)
+	}
 
-		if r.Computer.Halted {
-			break
-		}
+	fmt.Println("number of panes painted at least once:", len(r1.Seen))
 
-		colorToDraw, directionToMove := r.Computer.Output[0], r.Computer.Output[1]
+	// Part two starts at a white panel.
+	r2 := newRobot(colorWhite)
+	if err := intcode.Run(intcode.New(sequence), r2); err != nil {
+		log.Fatal(err)
+	}
 
-		r.draw(colorToDraw)
-		r.turn(directionToMove)
+	r2.show()
 
-		// Reset output
-		r.Computer.Output = []int{}
+	text, err := ocr.Recognize(r2.lit())
+	if err != nil {
+		log.Printf("could not read all letters: %s", err.Error())
 	}
-}
 
-func (r *robot) turn(turnDirection int) {
-	switch turnDirection {
-	case 0:
-		switch r.Direction {
-		case directionUp:
-			r.Y--
-		case directionLeft:
-			r.X++
-		case directionDown:
-			r.Y++
-		case directionRight:
-			r.X--
-		}
-
-		r.Direction++
+	fmt.Println("part two:", text)
 
-		if r.Direction > 3 {
-			r.Direction = directionUp
-		}
+	exports := []struct {
+		filename string
+		write    func(string) error
+	}{
+		{*pngFile, func(f string) error { return r2.writePNG(f, p) }},
+		{*svgFile, func(f string) error { return r2.writeSVG(f, p) }},
+		{*gifFile, func(f string) error { return r2.writeGIF(f, p, *gifDelay) }},
+	}
 
-	case 1:
-		switch r.Direction {
-		case directionUp:
-			r.Y++
-		case directionLeft:
-			r.X--
-		case directionDown:
-			r.Y--
-		case directionRight:
-			r.X++
+	for _, e := range exports {
+		if e.filename == "" {
+			continue
 		}
 
-		r.Direction--
-
-		if r.Direction < 0 {
-			r.Direction = directionRight
+		if err := e.write(e.filename); err != nil {
+			log.Fatalf("could not write %s: %s", e.filename, err.Error())
 		}
 	}
 }
 
-func (r *robot) draw(color int) {
-	r.Seen[fmt.Sprintf("%d,%d", r.X, r.Y)] = struct{}{}
-
-	switch color {
-	case 0:
-		r.Grid[r.X][r.Y] = black
-	case 1:
-		r.Grid[r.X][r.Y] = white
+// newRobot creates a robot standing on a panel with the given color.
+func newRobot(start color) *robot {
+	r := robot{
+		Heading: grid.Up,
+		Seen:    map[grid.Point]struct{}{},
+		Grid:    grid.Grid{},
 	}
-}
 
-func (r *robot) arrow() string {
-	switch r.Direction {
-	case directionUp:
-		return "^"
-	case directionLeft:
-		return "<"
-	case directionDown:
-		return "v"
-	case directionRight:
-		return ">"
+	if start != colorBlack {
+		r.Grid[r.Position] = int(start)
+		r.History = append(r.History, paintStep{Position: r.Position, Color: start})
 	}
 
-	return "O"
+	return &r
 }
 
-func (r *robot) show() {
-	fmt.Printf("current: %d,%d (%d)\n", r.X, r.Y, r.Direction)
+// Observe returns the color of the panel the robot is standing on. All panels
+// are black until painted.
+func (r *robot) Observe() []int {
+	return []int{r.Grid[r.Position]}
+}
 
-	val := r.Grid[r.X][r.Y]
+// Consume paints the current panel and turns the robot.
+func (r *robot) Consume(output []int) error {
+	colorToDraw, turnCode := output[0], output[1]
 
-	r.Grid[r.X][r.Y] = r.arrow()
+	r.draw(colorToDraw)
 
-	for _, row := range r.Grid {
-		fmt.Println(strings.Trim(strings.ReplaceAll(fmt.Sprint(row), " ", ""), "[]"))
-	}
+	return r.turn(turnCode)
+}
 
-	r.Grid[r.X][r.Y] = val
+// OutputSize returns two since the program outputs a color to paint followed
+// by a turn code.
+func (r *robot) OutputSize() int {
+	return 2
 }
 
-func (c *computer) process() {
-	opCode := c.Sequence[c.Pointer] % 100
+// Done returns false since the robot runs until the program halts.
+func (r *robot) Done() bool {
+	return false
+}
 
-	// Halt code found, stop processing.
-	if opCode == opCodeHalt {
-		c.Halted = true
-		return
+// turn rotates the robot according to the turn code and moves it one step
+// forward in the new heading.
+func (r *robot) turn(code int) error {
+	rotate, ok := turnMap[code]
+	if !ok {
+		return fmt.Errorf("unknown turn code %d at %d,%d", code, r.Position.X, r.Position.Y)
 	}
 
-	getPointer := func(argumentPosition int) int {
-		var (
-			pointer       = 0
-			modePositions = c.Sequence[c.Pointer] / 100
-			positions     = map[int]int{
-				1: modePositions % 10,
-				2: modePositions % 100 / 10,
-				3: modePositions % 1000 / 100,
-			}
-		)
-
-		switch positions[argumentPosition] {
-		case paramModePosition:
-			pointer = c.Sequence[c.Pointer+argumentPosition]
-		case paramModeImmediate:
-			pointer = c.Pointer + argumentPosition
-		case paramModeRelative:
-			pointer = c.Sequence[c.Pointer+argumentPosition] + c.Base
-		}
+	r.Heading = rotate(r.Heading)
+	r.Position = r.Position.Move(r.Heading, 1)
 
-		if pointer >= len(c.Sequence) {
-			c.Sequence = append(c.Sequence, make([]int, pointer-len(c.Sequence)+1)...)
-		}
+	return nil
+}
 
-		return pointer
-	}
+func (r *robot) draw(c int) {
+	r.Seen[r.Position] = struct{}{}
 
-	sequenceFor := func(pos int) int {
-		return c.Sequence[getPointer(pos)]
+	switch color(c) {
+	case colorBlack, colorWhite:
+		r.Grid[r.Position] = c
+		r.History = append(r.History, paintStep{Position: r.Position, Color: color(c)})
 	}
+}
 
-	switch opCode {
-	case opCodeAdd:
-		c.Sequence[getPointer(3)] = sequenceFor(1) + sequenceFor(2)
-
-	case opCodeMultiply:
-		c.Sequence[getPointer(3)] = sequenceFor(1) * sequenceFor(2)
-
-	case opCodeStore:
-		c.Sequence[getPointer(1)] = c.Input
+// bounds returns the rectangle containing every panel painted or visited by
+// the robot.
+func (r *robot) bounds() grid.Rect {
+	return r.Grid.Bounds(r.Position)
+}
 
-	case opCodeOutput:
-		c.Output = append(c.Output, sequenceFor(1))
+// lit returns every panel in the bounding box, lit if it's painted white.
+func (r *robot) lit() [][]bool {
+	var (
+		bounds = r.bounds()
+		lit    = [][]bool{}
+	)
 
-		if c.PauseAtOutput {
-			c.Pointer += jumpMap[opCodeOutput]
-			return
-		}
+	for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+		row := []bool{}
 
-	case opCodeJumpIfTrue:
-		if c.Sequence[getPointer(1)] != 0 {
-			c.Pointer = sequenceFor(2) - jumpMap[opCodeJumpIfTrue]
+		for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+			row = append(row, color(r.Grid[grid.Point{X: x, Y: y}]) == colorWhite)
 		}
 
-	case opCodeJumpIfFalse:
-		if c.Sequence[getPointer(1)] == 0 {
-			c.Pointer = sequenceFor(2) - jumpMap[opCodeJumpIfFalse]
-		}
+		lit = append(lit, row)
+	}
 
-	case opCodeLessThan:
-		if c.Sequence[getPointer(1)] < c.Sequence[getPointer(2)] {
-			c.Sequence[getPointer(3)] = 1
-		} else {
-			c.Sequence[getPointer(3)] = 0
-		}
+	return lit
+}
 
-	case opCodeEquals:
-		if c.Sequence[getPointer(1)] == c.Sequence[getPointer(2)] {
-			c.Sequence[getPointer(3)] = 1
-		} else {
-			c.Sequence[getPointer(3)] = 0
+func (r *robot) show() {
+	fmt.Printf("current: %d,%d (%s)\n", r.Position.X, r.Position.Y, r.Heading)
+
+	_ = grid.Render(os.Stdout, r.bounds(), func(p grid.Point) string {
+		v, ok := r.Grid[p]
+
+		switch {
+		case p == r.Position:
+			return r.Heading.String()
+		case ok:
+			return color(v).String()
+		default:
+			return "."
 		}
-
-	case opCodeAdjustBase:
-		c.Base += sequenceFor(1)
-
-	default:
-		panic(fmt.Sprintf("unknown instruction at position %d, opCode: %d", c.Pointer, opCode))
-	}
-
-	c.Pointer += jumpMap[opCode]
-	c.process()
+	})
 }
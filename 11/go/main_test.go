@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	"advent.of.code/grid"
+)
+
+func TestTurn(t *testing.T) {
+	cases := []struct {
+		heading  grid.Heading
+		code     int
+		want     grid.Heading
+		position grid.Point
+	}{
+		{heading: grid.Up, code: turnLeft, want: grid.Left, position: grid.Point{X: -1, Y: 0}},
+		{heading: grid.Up, code: turnRight, want: grid.Right, position: grid.Point{X: 1, Y: 0}},
+		{heading: grid.Up, code: turnAround, want: grid.Down, position: grid.Point{X: 0, Y: 1}},
+		{heading: grid.Up, code: turnNone, want: grid.Up, position: grid.Point{X: 0, Y: -1}},
+
+		{heading: grid.Right, code: turnLeft, want: grid.Up, position: grid.Point{X: 0, Y: -1}},
+		{heading: grid.Right, code: turnRight, want: grid.Down, position: grid.Point{X: 0, Y: 1}},
+		{heading: grid.Right, code: turnAround, want: grid.Left, position: grid.Point{X: -1, Y: 0}},
+		{heading: grid.Right, code: turnNone, want: grid.Right, position: grid.Point{X: 1, Y: 0}},
+
+		{heading: grid.Down, code: turnLeft, want: grid.Right, position: grid.Point{X: 1, Y: 0}},
+		{heading: grid.Down, code: turnRight, want: grid.Left, position: grid.Point{X: -1, Y: 0}},
+		{heading: grid.Down, code: turnAround, want: grid.Up, position: grid.Point{X: 0, Y: -1}},
+		{heading: grid.Down, code: turnNone, want: grid.Down, position: grid.Point{X: 0, Y: 1}},
+
+		{heading: grid.Left, code: turnLeft, want: grid.Down, position: grid.Point{X: 0, Y: 1}},
+		{heading: grid.Left, code: turnRight, want: grid.Up, position: grid.Point{X: 0, Y: -1}},
+		{heading: grid.Left, code: turnAround, want: grid.Right, position: grid.Point{X: 1, Y: 0}},
+		{heading: grid.Left, code: turnNone, want: grid.Left, position: grid.Point{X: -1, Y: 0}},
+	}
+
+	for _, tc := range cases {
+		r := newRobot(colorBlack)
+		r.Heading = tc.heading
+
+		if err := r.turn(tc.code); err != nil {
+			t.Fatalf("heading %s, code %d: unexpected error: %s", tc.heading, tc.code, err)
+		}
+
+		if r.Heading != tc.want {
+			t.Errorf("heading %s, code %d: got heading %s, want %s", tc.heading, tc.code, r.Heading, tc.want)
+		}
+
+		if r.Position != tc.position {
+			t.Errorf("heading %s, code %d: got position %v, want %v", tc.heading, tc.code, r.Position, tc.position)
+		}
+	}
+}
+
+func TestTurnUnknownCode(t *testing.T) {
+	for _, code := range []int{-1, 4, 99} {
+		r := newRobot(colorBlack)
+
+		if err := r.turn(code); err == nil {
+			t.Errorf("code %d: expected an error", code)
+		}
+
+		if r.Heading != grid.Up || r.Position != (grid.Point{}) {
+			t.Errorf("code %d: robot moved to %v heading %s", code, r.Position, r.Heading)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	imgcolor "image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"advent.of.code/grid"
+)
+
+// palette holds the colors and cell size used when exporting the hull.
+type palette struct {
+	CellSize int
+	Black    imgcolor.RGBA
+	White    imgcolor.RGBA
+}
+
+// paintStep represents a single panel painted by the robot.
+type paintStep struct {
+	Position grid.Point
+	Color    color
+}
+
+// parseHexColor parses a color in the format #rrggbb.
+func parseHexColor(s string) (imgcolor.RGBA, error) {
+	s = strings.TrimPrefix(s, "#")
+
+	if len(s) != 6 {
+		return imgcolor.RGBA{}, fmt.Errorf("expected color as #rrggbb, got '%s'", s)
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return imgcolor.RGBA{}, fmt.Errorf("invalid color '%s': %w", s, err)
+	}
+
+	return imgcolor.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+func (p palette) colorFor(c color) imgcolor.RGBA {
+	if c == colorWhite {
+		return p.White
+	}
+
+	return p.Black
+}
+
+// image renders the grid with every panel as a square of the cell size.
+// Panels never painted are black.
+func (p palette) image(g grid.Grid, bounds grid.Rect) *image.Paletted {
+	var (
+		width  = bounds.Width() * p.CellSize
+		height = bounds.Height() * p.CellSize
+		img    = image.NewPaletted(
+			image.Rect(0, 0, width, height),
+			imgcolor.Palette{p.Black, p.White},
+		)
+	)
+
+	for c, v := range g {
+		p.fill(img, c, bounds.Min, color(v))
+	}
+
+	return img
+}
+
+func (p palette) fill(img *image.Paletted, c, minimum grid.Point, v color) {
+	var (
+		top   = (c.Y - minimum.Y) * p.CellSize
+		left  = (c.X - minimum.X) * p.CellSize
+		index = uint8(img.Palette.Index(p.colorFor(v)))
+	)
+
+	for y := top; y < top+p.CellSize; y++ {
+		for x := left; x < left+p.CellSize; x++ {
+			img.SetColorIndex(x, y, index)
+		}
+	}
+}
+
+func (r *robot) writePNG(filename string, p palette) error {
+	bounds := r.bounds()
+
+	return writeFile(filename, func(w io.Writer) error {
+		return png.Encode(w, p.image(r.Grid, bounds))
+	})
+}
+
+func (r *robot) writeSVG(filename string, p palette) error {
+	var (
+		bounds = r.bounds()
+		width  = bounds.Width() * p.CellSize
+		height = bounds.Height() * p.CellSize
+		hex    = func(c imgcolor.RGBA) string {
+			return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+		}
+	)
+
+	return writeFile(filename, func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+
+		fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+		fmt.Fprintf(bw, "  <rect width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", width, height, hex(p.Black))
+
+		for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+				if color(r.Grid[grid.Point{X: x, Y: y}]) != colorWhite {
+					continue
+				}
+
+				fmt.Fprintf(
+					bw, "  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+					(x-bounds.Min.X)*p.CellSize, (y-bounds.Min.Y)*p.CellSize, p.CellSize, p.CellSize, hex(p.White),
+				)
+			}
+		}
+
+		fmt.Fprintln(bw, "</svg>")
+
+		return bw.Flush()
+	})
+}
+
+// writeGIF writes an animated GIF with one frame for every panel painted.
+// The delay between frames is given in 100ths of a second.
+func (r *robot) writeGIF(filename string, p palette, delay int) error {
+	var (
+		bounds    = r.bounds()
+		animation = gif.GIF{}
+		painted   = grid.Grid{}
+	)
+
+	for _, step := range r.History {
+		painted[step.Position] = int(step.Color)
+
+		animation.Image = append(animation.Image, p.image(painted, bounds))
+		animation.Delay = append(animation.Delay, delay)
+	}
+
+	return writeFile(filename, func(w io.Writer) error {
+		return gif.EncodeAll(w, &animation)
+	})
+}
+
+func writeFile(filename string, write func(io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
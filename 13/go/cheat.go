@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"advent.of.code/grid"
+	"advent.of.code/intcode"
+)
+
+// memoryMap holds the addresses where the game keeps its state.
+type memoryMap struct {
+	Screen    int
+	Width     int
+	PaddleRow int
+	Score     int
+	Blocks    int
+}
+
+// memoryProbe plays the game like the ball tracker while narrowing down the
+// addresses holding the score and the number of blocks left by checking the
+// memory every time they change.
+type memoryProbe struct {
+	Computer   *intcode.Computer
+	Memory     *memoryMap
+	score      []int
+	blocks     []int
+	lastScore  int
+	lastBlocks int
+}
+
+// locateMemory plays the game on a copy of the program until it has found
+// where the screen, score and number of blocks left are stored.
+func locateMemory(sequence []int) (*memoryMap, error) {
+	var (
+		c = intcode.New(sequence)
+		g = newGame()
+		m = &memoryMap{Screen: -1, Score: -1, Blocks: -1}
+	)
+
+	g.Controller = &memoryProbe{Computer: c, Memory: m, lastScore: -1, lastBlocks: -1}
+
+	if err := intcode.Run(c, g); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case m.Screen < 0:
+		return nil, errors.New("could not find the screen in memory")
+	case m.Score < 0:
+		return nil, errors.New("could not find the score in memory")
+	case m.Blocks < 0:
+		return nil, errors.New("could not find the number of blocks in memory")
+	}
+
+	return m, nil
+}
+
+func (p *memoryProbe) joystick(g *game) int {
+	memory := p.Computer.Sequence
+
+	if p.Memory.Screen < 0 {
+		p.locateScreen(g)
+	}
+
+	if score := g.Display; score != p.lastScore && score > 0 {
+		p.score = filterAddresses(memory, p.score, score)
+		p.lastScore = score
+	}
+
+	if blocks := g.blocksLeft(); blocks != p.lastBlocks {
+		p.blocks = filterAddresses(memory, p.blocks, blocks)
+		p.lastBlocks = blocks
+	}
+
+	if len(p.score) == 1 && len(p.blocks) == 1 {
+		p.Memory.Score = p.score[0]
+		p.Memory.Blocks = p.blocks[0]
+		g.Quit = true
+	}
+
+	return ballTracker{}.joystick(g)
+}
+
+// locateScreen finds the screen by searching the memory for the row with the
+// paddle. The screen is stored row by row with the width of the top wall.
+func (p *memoryProbe) locateScreen(g *game) {
+	var (
+		memory = p.Computer.Sequence
+		y      = g.PaddlePosition.Y
+		width  = g.Grid.Bounds(grid.Point{}).Max.X + 1
+		row    = make([]int, width)
+	)
+
+	for x := range row {
+		row[x] = g.Grid[grid.Point{X: x, Y: y}]
+	}
+
+	for address := 0; address+width <= len(memory); address++ {
+		if !equal(memory[address:address+width], row) {
+			continue
+		}
+
+		p.Memory.PaddleRow = address
+		p.Memory.Screen = address - y*width
+		p.Memory.Width = width
+
+		return
+	}
+}
+
+// filterAddresses returns the addresses holding value. If no addresses are
+// given every address in the memory is checked.
+func filterAddresses(memory, addresses []int, value int) []int {
+	filtered := []int{}
+
+	if addresses == nil {
+		for address, v := range memory {
+			if v == value {
+				filtered = append(filtered, address)
+			}
+		}
+
+		return filtered
+	}
+
+	for _, address := range addresses {
+		if memory[address] == value {
+			filtered = append(filtered, address)
+		}
+	}
+
+	return filtered
+}
+
+// widenPaddle turns every empty tile in the paddle row into a wall so the ball
+// can never fall out of the screen.
+func (m *memoryMap) widenPaddle(memory []int) {
+	for address := m.PaddleRow; address < m.PaddleRow+m.Width; address++ {
+		if memory[address] == tileEmpty {
+			memory[address] = tileWall
+		}
+	}
+}
+
+func (m *memoryMap) report(w io.Writer) {
+	fmt.Fprintf(w, "screen at %d (%d tiles wide)\n", m.Screen, m.Width)
+	fmt.Fprintf(w, "paddle row at %d\n", m.PaddleRow)
+	fmt.Fprintf(w, "score at %d\n", m.Score)
+	fmt.Fprintf(w, "blocks left at %d\n", m.Blocks)
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Keys read from the terminal in interactive mode.
+const (
+	keyArrowUp    = "\033[A"
+	keyArrowDown  = "\033[B"
+	keyArrowRight = "\033[C"
+	keyArrowLeft  = "\033[D"
+	keySpace      = " "
+	keySave       = "s"
+	keyQuit       = "q"
+)
+
+// controller decides how to move the joystick whenever the game needs input.
+type controller interface {
+	joystick(g *game) int
+}
+
+// nolint: gochecknoglobals
+var controllers = map[string]controller{
+	"tracker":    ballTracker{},
+	"predictive": predictor{},
+	"idle":       idle{},
+}
+
+// ballTracker moves the paddle towards the ball's current position.
+type ballTracker struct{}
+
+// predictor moves the paddle towards where the ball will land by simulating
+// its trajectory, falling back to the ball's current position while the ball
+// is on its way up.
+type predictor struct{}
+
+// idle never moves the paddle, only useful with the paddle widened into a
+// wall.
+type idle struct{}
+
+// human renders the game and reads the joystick from the keyboard.
+type human struct {
+	In *bufio.Reader
+}
+
+func (ballTracker) joystick(g *game) int {
+	return moveTowards(g, g.BallPosition.X)
+}
+
+func (predictor) joystick(g *game) int {
+	x, ok := g.landing()
+	if !ok {
+		x = g.BallPosition.X
+	}
+
+	return moveTowards(g, x)
+}
+
+func (idle) joystick(*game) int {
+	return joystickNeutral
+}
+
+// moveTowards returns the joystick input to move the paddle towards x.
+func moveTowards(g *game, x int) int {
+	switch {
+	case x > g.PaddlePosition.X:
+		return joytickRight
+	case x < g.PaddlePosition.X:
+		return joystickLeft
+	default:
+		return joystickNeutral
+	}
+}
+
+func (h human) joystick(g *game) int {
+	g.Renderer.render(g.Out, g.Grid, g.Display)
+	fmt.Fprintln(g.Out, "left/right to move, up/down or space to wait, s to save, q to quit")
+
+	for {
+		key, err := readKey(h.In)
+		if err != nil {
+			g.Quit = true
+			return joystickNeutral
+		}
+
+		switch key {
+		case keyArrowLeft:
+			return joystickLeft
+		case keyArrowRight:
+			return joytickRight
+		case keyArrowUp, keyArrowDown, keySpace:
+			return joystickNeutral
+		case keySave:
+			g.SaveRequested = true
+		case keyQuit:
+			g.Quit = true
+			return joystickNeutral
+		}
+	}
+}
+
+// readKey reads a single key, which is either one character or an escape
+// sequence like the arrow keys, so several keys pressed at once are all seen.
+func readKey(r *bufio.Reader) (string, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+
+	if b != '\033' {
+		return string(b), nil
+	}
+
+	// Escape sequences for the arrow keys are ESC [ followed by a letter.
+	key := []byte{b}
+
+	for len(key) < len(keyArrowUp) && r.Buffered() > 0 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+
+		key = append(key, b)
+	}
+
+	return string(key), nil
+}
+
+// rawTerminal turns off line buffering and echo for the terminal so every key
+// can be read as soon as it's pressed. The returned function restores the
+// previous settings.
+func rawTerminal() (func(), error) {
+	stty := func(args ...string) (string, error) {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = os.Stdin
+
+		out, err := cmd.Output()
+
+		return strings.TrimSpace(string(out)), err
+	}
+
+	previous, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("could not read terminal settings: %w", err)
+	}
+
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, fmt.Errorf("could not set terminal to raw mode: %w", err)
+	}
+
+	return func() {
+		_, _ = stty(previous)
+	}, nil
+}
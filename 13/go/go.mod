@@ -1,3 +1,13 @@
 module advent.of.code/13
 
 go 1.13
+
+require (
+	advent.of.code/grid v0.0.0
+	advent.of.code/intcode v0.0.0
+)
+
+replace (
+	advent.of.code/grid => ../../grid/go
+	advent.of.code/intcode => ../../intcode/go
+)
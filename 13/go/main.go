@@ -1,35 +1,18 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"log"
 	"os"
-	"os/exec"
-	"strconv"
-	"strings"
+	"os/signal"
 	"time"
-)
 
-// * ParamModePosition  -> Use the value found at slice[n]
-// * ParamModeImmediate -> Use the value n
-// * ParamModeRelative  -> Use the value found at slice[BASE+n]
-const (
-	paramModePosition = iota
-	paramModeImmediate
-	paramModeRelative
-)
-
-const (
-	opCodeAdd         = 1
-	opCodeMultiply    = 2
-	opCodeStore       = 3
-	opCodeOutput      = 4
-	opCodeJumpIfTrue  = 5
-	opCodeJumpIfFalse = 6
-	opCodeLessThan    = 7
-	opCodeEquals      = 8
-	opCodeAdjustBase  = 9
-	opCodeHalt        = 99
+	"advent.of.code/grid"
+	"advent.of.code/intcode"
 )
 
 const (
@@ -46,6 +29,7 @@ const (
 	joytickRight    = 1
 )
 
+// nolint: gochecknoglobals
 var tileMap = map[int]string{
 	tileEmpty:            "  ",
 	tileWall:             "🧱",
@@ -54,233 +38,304 @@ var tileMap = map[int]string{
 	tileBall:             "🎾",
 }
 
-// nolint: gochecknoglobals
-var jumpMap = map[int]int{
-	opCodeAdd:         4,
-	opCodeMultiply:    4,
-	opCodeStore:       2,
-	opCodeOutput:      2,
-	opCodeJumpIfTrue:  3,
-	opCodeJumpIfFalse: 3,
-	opCodeLessThan:    4,
-	opCodeEquals:      4,
-	opCodeAdjustBase:  2,
-}
-
-type computer struct {
-	Input         int
-	Output        []int
-	Pointer       int
-	Base          int
-	Sequence      []int
-	PauseAtOutput bool
-	Halted        bool
+// game represents the arcade cabinet. It's drawn by the program and keeps
+// track of the score, the ball and the paddle to move the joystick.
+type game struct {
+	Grid             grid.Grid
+	Objects          map[int]int
+	Display          int
+	BallPosition     grid.Point
+	PrevBallPosition grid.Point
+	BallVelocity     grid.Point
+	PaddlePosition   grid.Point
+	Renderer         renderer
+	Controller       controller
+	FrameDelay       time.Duration
+	Out              io.Writer
+	Frame            int
+	SaveRequested    bool
+	PaddleMoves      int
+	Quit             bool
 }
 
 func main() {
 	var (
-		line, _        = ioutil.ReadFile(os.Args[1])
-		stringSequence = strings.Split(string(line), ",")
-		sequence       = make([]int, len(stringSequence))
+		headless     = flag.Bool("headless", false, "don't render the game, only print the number of blocks and final score")
+		rendererName = flag.String("renderer", "ansi", "how to render the game: ansi, ascii or none")
+		frameDelay   = flag.Duration("delay", 30*time.Millisecond, "delay after each frame rendered")
+		play         = flag.Bool("play", false, "play the game with the arrow keys instead of the paddle AI")
+		recordFile   = flag.String("record", "", "record every joystick input to this file")
+		replayFile   = flag.String("replay", "", "replay the joystick inputs from this file and verify the final score")
+		aiName       = flag.String("ai", "tracker", "paddle AI to use: tracker, predictive or idle")
+		metrics      = flag.Bool("metrics", false, "print the number of frames and paddle moves used")
+		cheatReport  = flag.Bool("cheat-report", false, "print where the game state is stored in memory")
+		cheatWall    = flag.Bool("cheat-wall", false, "widen the paddle into a wall covering the whole row")
+		cheatScore   = flag.Int("cheat-score", -1, "set the score in memory before the game starts")
+		cheatBlocks  = flag.Int("cheat-blocks", -1, "set the number of blocks left in memory before the game starts")
+		saveFile     = flag.String("save", "breakout.json", "file to save the game to, press s while playing to save")
+		saveAt       = flag.Int("save-at", -1, "save the game when reaching this frame")
+		resumeFile   = flag.String("resume", "", "resume a game saved to this file")
 	)
 
-	for i := range sequence {
-		sequence[i], _ = strconv.Atoi(strings.TrimSpace(stringSequence[i]))
-	}
+	flag.Parse()
 
-	run(sequence, 0)
-}
+	if flag.NArg() < 1 {
+		log.Fatal("missing file as input")
+	}
 
-func run(sequence []int, input int) {
-	c := computer{Input: input, PauseAtOutput: true}
+	newRenderer, ok := renderers[*rendererName]
+	if !ok {
+		log.Fatalf("unknown renderer '%s'", *rendererName)
+	}
 
-	c.Sequence = make([]int, len(sequence))
-	copy(c.Sequence, sequence)
+	ai, ok := controllers[*aiName]
+	if !ok {
+		log.Fatalf("unknown AI '%s'", *aiName)
+	}
 
-	var (
-		width            = 40
-		height           = 25
-		row              = make([][]int, height)
-		objects          = make(map[int]int)
-		display          = 0
-		ballPosition     = []int{0, 0}
-		prevBallPosition = []int{0, 0}
-		paddlePosition   = []int{0, 0}
-	)
+	line, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("could not read file: %s", err.Error())
+	}
 
-	// Create a grid for visualization
-	for i := range row {
-		row[i] = make([]int, width)
+	sequence, err := intcode.Parse(line)
+	if err != nil {
+		log.Fatalf("could not parse program: %s", err.Error())
 	}
 
 	// Set quarters to 2
-	c.Sequence[0] = 2
+	sequence[0] = 2
 
-	showState := func() {
-		cmd := exec.Command("clear")
-		cmd.Stdout = os.Stdout
+	c := intcode.New(sequence)
+	g := newGame()
 
-		if err := cmd.Run(); err != nil {
-			panic(err)
+	if *resumeFile != "" {
+		if *recordFile != "" {
+			log.Fatal("can't record a resumed game")
 		}
 
-		for i := range row {
-			for j := range row[i] {
-				fmt.Print(tileMap[row[i][j]])
-			}
-
-			fmt.Println("")
+		s, err := readSnapshot(*resumeFile)
+		if err != nil {
+			log.Fatalf("could not resume game: %s", err.Error())
 		}
 
-		time.Sleep(30 * time.Millisecond)
+		s.restore(c, g)
 	}
 
-	// Update the current computer/amplifier
-	for {
-		c.process()
+	// The game stores its state at the same addresses when resumed so we can
+	// locate it from the start of the game.
+	if *cheatReport || *cheatWall || *cheatScore >= 0 || *cheatBlocks >= 0 {
+		m, err := locateMemory(sequence)
+		if err != nil {
+			log.Fatalf("could not locate game state: %s", err.Error())
+		}
 
-		if c.Halted {
-			break
+		if *cheatReport {
+			m.report(os.Stdout)
 		}
 
-		if len(c.Output)%3 == 0 {
-			x, y, objectID := c.Output[0], c.Output[1], c.Output[2]
-
-			// Draw tiles if we're inbound.
-			if x >= 0 && y >= 0 {
-				row[y][x] = objectID
-			}
-
-			// Calculate number of objects
-			objects[objectID]++
-
-			// Update object positions if we're at a paddle or ball position.
-			switch objectID {
-			case tileHorizontalPaddle:
-				paddlePosition = []int{x, y}
-			case tileBall:
-				ballPosition = []int{x, y}
-			}
-
-			// Decide how to move the paddle based on the relation to the ball.
-			switch {
-			case ballPosition[0] > paddlePosition[0]:
-				c.Input = joytickRight
-			case ballPosition[0] < paddlePosition[0]:
-				c.Input = joystickLeft
-			default:
-				c.Input = joystickNeutral
-			}
-
-			// Set score when given instruction is shown.
-			if x == -1 && y == 0 {
-				display = objectID
-			}
-
-			if prevBallPosition[0] != ballPosition[0] || prevBallPosition[1] != ballPosition[1] {
-				showState()
-
-				prevBallPosition = []int{ballPosition[0], ballPosition[1]}
-			}
-
-			// Reset the output
-			c.Output = []int{}
+		if *cheatWall {
+			m.widenPaddle(c.Sequence)
+		}
+
+		if *cheatScore >= 0 {
+			c.Sequence[m.Score] = *cheatScore
+		}
+
+		if *cheatBlocks >= 0 {
+			c.Sequence[m.Blocks] = *cheatBlocks
 		}
 	}
 
-	fmt.Println("number of blocks", objects[tileBlock])
-	fmt.Println("final score", display)
-}
+	g.Renderer = newRenderer()
+	g.FrameDelay = *frameDelay
+	g.Controller = ai
 
-func (c *computer) process() {
-	opCode := c.Sequence[c.Pointer] % 100
+	if *headless {
+		if *play {
+			log.Fatal("can't play the game headless")
+		}
 
-	// Halt code found, stop processing.
-	if opCode == opCodeHalt {
-		c.Halted = true
-		return
+		g.Renderer = noneRenderer{}
+		g.FrameDelay = 0
 	}
 
-	getPointer := func(argumentPosition int) int {
-		var (
-			pointer       = 0
-			modePositions = c.Sequence[c.Pointer] / 100
-			positions     = map[int]int{
-				1: modePositions % 10,
-				2: modePositions % 100 / 10,
-				3: modePositions % 1000 / 100,
-			}
-		)
-
-		switch positions[argumentPosition] {
-		case paramModePosition:
-			pointer = c.Sequence[c.Pointer+argumentPosition]
-		case paramModeImmediate:
-			pointer = c.Pointer + argumentPosition
-		case paramModeRelative:
-			pointer = c.Sequence[c.Pointer+argumentPosition] + c.Base
-		}
+	if *play && *replayFile != "" {
+		log.Fatal("can't play and replay a game at the same time")
+	}
+
+	var recorded, replayed *replay
 
-		if pointer >= len(c.Sequence) {
-			c.Sequence = append(c.Sequence, make([]int, pointer-len(c.Sequence)+1)...)
+	if *replayFile != "" {
+		if replayed, err = readReplay(*replayFile); err != nil {
+			log.Fatalf("could not read replay: %s", err.Error())
 		}
 
-		return pointer
+		g.Controller = replayer{Replay: replayed}
 	}
 
-	sequenceFor := func(pos int) int {
-		return c.Sequence[getPointer(pos)]
+	if *play {
+		g.Controller = human{In: bufio.NewReader(os.Stdin)}
 	}
 
-	switch opCode {
-	case opCodeAdd:
-		c.Sequence[getPointer(3)] = sequenceFor(1) + sequenceFor(2)
+	if *recordFile != "" {
+		recorded = &replay{}
+		g.Controller = recorder{Controller: g.Controller, Replay: recorded}
+	}
+
+	if *play || *saveAt >= 0 {
+		g.Controller = saver{Controller: g.Controller, Computer: c, Filename: *saveFile, Frame: *saveAt}
+	}
+
+	if *play {
+		restore, err := rawTerminal()
+		if err != nil {
+			log.Fatal(err)
+		}
 
-	case opCodeMultiply:
-		c.Sequence[getPointer(3)] = sequenceFor(1) * sequenceFor(2)
+		// Restore the terminal if we're interrupted.
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
 
-	case opCodeStore:
-		c.Sequence[getPointer(1)] = c.Input
+		go func() {
+			<-interrupt
+			restore()
+			os.Exit(1)
+		}()
 
-	case opCodeOutput:
-		c.Output = append(c.Output, sequenceFor(1))
+		// The game only moves when we press a key so there's no need to wait
+		// between frames.
+		g.FrameDelay = 0
 
-		if c.PauseAtOutput {
-			c.Pointer += jumpMap[opCodeOutput]
-			return
+		err = intcode.Run(c, g)
+
+		restore()
+
+		if err != nil {
+			log.Fatal(err)
 		}
+	} else if err := intcode.Run(c, g); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("number of blocks", g.Objects[tileBlock])
+	fmt.Println("final score", g.Display)
 
-	case opCodeJumpIfTrue:
-		if c.Sequence[getPointer(1)] != 0 {
-			c.Pointer = sequenceFor(2) - jumpMap[opCodeJumpIfTrue]
+	if *metrics {
+		g.showMetrics()
+	}
+
+	if recorded != nil {
+		recorded.Score = g.Display
+
+		if err := recorded.write(*recordFile); err != nil {
+			log.Fatalf("could not write replay: %s", err.Error())
 		}
+	}
 
-	case opCodeJumpIfFalse:
-		if c.Sequence[getPointer(1)] == 0 {
-			c.Pointer = sequenceFor(2) - jumpMap[opCodeJumpIfFalse]
+	if replayed != nil {
+		if g.Frame < len(replayed.Inputs) {
+			log.Fatalf("game ended after %d of %d recorded inputs", g.Frame, len(replayed.Inputs))
 		}
 
-	case opCodeLessThan:
-		if c.Sequence[getPointer(1)] < c.Sequence[getPointer(2)] {
-			c.Sequence[getPointer(3)] = 1
-		} else {
-			c.Sequence[getPointer(3)] = 0
+		if g.Display != replayed.Score {
+			log.Fatalf("replay ended with score %d, expected %d", g.Display, replayed.Score)
 		}
 
-	case opCodeEquals:
-		if c.Sequence[getPointer(1)] == c.Sequence[getPointer(2)] {
-			c.Sequence[getPointer(3)] = 1
-		} else {
-			c.Sequence[getPointer(3)] = 0
+		fmt.Println("replay verified")
+	}
+}
+
+func newGame() *game {
+	g := game{
+		Grid:       grid.Grid{},
+		Objects:    make(map[int]int),
+		Renderer:   noneRenderer{},
+		Controller: ballTracker{},
+		Out:        os.Stdout,
+	}
+
+	return &g
+}
+
+// Observe asks the controller how to move the joystick. Every input is a new
+// frame.
+func (g *game) Observe() []int {
+	joystick := g.Controller.joystick(g)
+	g.Frame++
+
+	if joystick != joystickNeutral {
+		g.PaddleMoves++
+	}
+
+	return []int{joystick}
+}
+
+// Consume draws a tile or updates the score.
+func (g *game) Consume(output []int) error {
+	x, y, objectID := output[0], output[1], output[2]
+
+	// Draw tiles if we're inbound.
+	if x >= 0 && y >= 0 {
+		g.Grid[grid.Point{X: x, Y: y}] = objectID
+	}
+
+	// Calculate number of objects
+	g.Objects[objectID]++
+
+	// Update object positions if we're at a paddle or ball position.
+	switch objectID {
+	case tileHorizontalPaddle:
+		g.PaddlePosition = grid.Point{X: x, Y: y}
+	case tileBall:
+		g.BallVelocity = grid.Point{X: x, Y: y}.Sub(g.BallPosition)
+		g.BallPosition = grid.Point{X: x, Y: y}
+	}
+
+	// Set score when given instruction is shown.
+	if x == -1 && y == 0 {
+		g.Display = objectID
+	}
+
+	if g.PrevBallPosition != g.BallPosition {
+		g.showState()
+
+		g.PrevBallPosition = g.BallPosition
+	}
+
+	return nil
+}
+
+// OutputSize returns three since the program outputs x, y and a tile.
+func (g *game) OutputSize() int {
+	return 3
+}
+
+// Done returns true if the player quit, otherwise the game runs until the
+// program halts.
+func (g *game) Done() bool {
+	return g.Quit
+}
+
+// blocksLeft returns the number of blocks still on the grid.
+func (g *game) blocksLeft() int {
+	blocks := 0
+
+	for _, tile := range g.Grid {
+		if tile == tileBlock {
+			blocks++
 		}
+	}
 
-	case opCodeAdjustBase:
-		c.Base += sequenceFor(1)
+	return blocks
+}
 
-	default:
-		panic(fmt.Sprintf("unknown instruction at position %d, opCode: %d", c.Pointer, opCode))
+func (g *game) showMetrics() {
+	if blocks := g.blocksLeft(); blocks > 0 {
+		fmt.Println("blocks left", blocks, "after", g.Frame, "frames")
+	} else {
+		fmt.Println("frames to win", g.Frame)
 	}
 
-	c.Pointer += jumpMap[opCode]
-	c.process()
+	fmt.Println("paddle moves", g.PaddleMoves)
 }
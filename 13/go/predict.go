@@ -0,0 +1,91 @@
+package main
+
+import "advent.of.code/grid"
+
+// maxPredictionSteps limits how far the ball trajectory is simulated.
+const maxPredictionSteps = 10000
+
+// landing simulates the ball from its current position and velocity until it
+// reaches the row above the paddle and returns that column. Blocks hit are
+// removed from a copy of the grid so the simulation follows the bounces in the
+// game. Returns false unless the ball is on its way down since bounces between
+// several blocks aren't always simulated correctly and a long trajectory would
+// leave the paddle far from the ball.
+func (g *game) landing() (int, bool) {
+	var (
+		x, y    = g.BallPosition.X, g.BallPosition.Y
+		dx, dy  = g.BallVelocity.X, g.BallVelocity.Y
+		paddleY = g.PaddlePosition.Y
+		bounds  = g.Grid.Bounds(grid.Point{})
+		tiles   = grid.Grid{}
+	)
+
+	if abs(dx) != 1 || dy != 1 {
+		return 0, false
+	}
+
+	for p, tile := range g.Grid {
+		tiles[p] = tile
+	}
+
+	// solid returns true if the ball bounces at the position, breaking it if
+	// it's a block. The paddle is ignored since that's where we're going.
+	solid := func(x, y int) bool {
+		p := grid.Point{X: x, Y: y}
+
+		if !bounds.Contains(p) {
+			return true
+		}
+
+		switch tiles[p] {
+		case tileWall:
+			return true
+		case tileBlock:
+			tiles[p] = tileEmpty
+			return true
+		}
+
+		return false
+	}
+
+	for i := 0; i < maxPredictionSteps; i++ {
+		if dy > 0 && y == paddleY-1 {
+			return x, true
+		}
+
+		// The ball can't go below the paddle so we'll never see it land.
+		if y >= paddleY {
+			return 0, false
+		}
+
+		bounced := false
+
+		if solid(x+dx, y) {
+			dx = -dx
+			bounced = true
+		}
+
+		if solid(x, y+dy) {
+			dy = -dy
+			bounced = true
+		}
+
+		// Only bounce on a corner if neither side bounced the ball.
+		if !bounced && solid(x+dx, y+dy) {
+			dx, dy = -dx, -dy
+		}
+
+		x += dx
+		y += dy
+	}
+
+	return 0, false
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+
+	"advent.of.code/grid"
+)
+
+// renderer draws the grid and score to a writer.
+type renderer interface {
+	render(w io.Writer, tiles grid.Grid, score int)
+}
+
+// nolint: gochecknoglobals
+var renderers = map[string]func() renderer{
+	"ansi":  func() renderer { return &ansiRenderer{} },
+	"ascii": func() renderer { return asciiRenderer{} },
+	"none":  func() renderer { return noneRenderer{} },
+}
+
+// nolint: gochecknoglobals
+var asciiTileMap = map[int]string{
+	tileEmpty:            " ",
+	tileWall:             "#",
+	tileBlock:            "=",
+	tileHorizontalPaddle: "-",
+	tileBall:             "o",
+}
+
+// ansiRenderer redraws the emoji tiles in place with escape codes.
+type ansiRenderer struct {
+	screen grid.Screen
+}
+
+// asciiRenderer prints every frame after the previous one with plain ASCII,
+// suitable for logs.
+type asciiRenderer struct{}
+
+// noneRenderer doesn't draw anything.
+type noneRenderer struct{}
+
+func (r *ansiRenderer) render(w io.Writer, tiles grid.Grid, score int) {
+	_ = r.screen.Draw(w, func(w io.Writer) {
+		writeGrid(w, tiles, tileMap)
+		fmt.Fprintln(w, "score:", score)
+	})
+}
+
+func (asciiRenderer) render(w io.Writer, tiles grid.Grid, score int) {
+	bw := bufio.NewWriter(w)
+
+	writeGrid(bw, tiles, asciiTileMap)
+	fmt.Fprintln(bw, "score:", score)
+	fmt.Fprintln(bw, "")
+
+	bw.Flush()
+}
+
+func (noneRenderer) render(io.Writer, grid.Grid, int) {}
+
+// writeGrid writes every tile drawn so far with the screen starting at the top
+// left corner, showing tiles not yet drawn as empty.
+func writeGrid(w io.Writer, tiles grid.Grid, glyphs map[int]string) {
+	bounds := tiles.Bounds(grid.Point{})
+
+	_ = grid.Render(w, bounds, tiles.Glyphs(glyphs, glyphs[tileEmpty]))
+}
+
+// showState renders the current state and waits for the frame delay.
+func (g *game) showState() {
+	g.Renderer.render(g.Out, g.Grid, g.Display)
+
+	if g.FrameDelay > 0 {
+		time.Sleep(g.FrameDelay)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// replayInput represents a joystick input and the frame it was given on.
+type replayInput struct {
+	Frame    int
+	Joystick int
+}
+
+// replay holds every joystick input given during a game and the final score.
+// It's stored as a line with the score followed by one line per input.
+//
+//	score 14538
+//	0 0
+//	1 -1
+type replay struct {
+	Score  int
+	Inputs []replayInput
+}
+
+// recorder records every joystick input given by another controller.
+type recorder struct {
+	Controller controller
+	Replay     *replay
+}
+
+// replayer gives the joystick inputs from a replay, one for each frame.
+type replayer struct {
+	Replay *replay
+}
+
+func (r recorder) joystick(g *game) int {
+	joystick := r.Controller.joystick(g)
+
+	r.Replay.Inputs = append(r.Replay.Inputs, replayInput{Frame: g.Frame, Joystick: joystick})
+
+	return joystick
+}
+
+// joystick returns the input recorded for the current frame. The game quits if
+// the replay has no more inputs.
+func (r replayer) joystick(g *game) int {
+	if g.Frame >= len(r.Replay.Inputs) {
+		g.Quit = true
+		return joystickNeutral
+	}
+
+	return r.Replay.Inputs[g.Frame].Joystick
+}
+
+func readReplay(filename string) (*replay, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return parseReplay(f)
+}
+
+func parseReplay(r io.Reader) (*replay, error) {
+	var (
+		rp         = replay{}
+		scanner    = bufio.NewScanner(r)
+		line       = 0
+		foundScore = false
+	)
+
+	for scanner.Scan() {
+		line++
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid replay line %d: '%s'", line, scanner.Text())
+		}
+
+		if fields[0] == "score" {
+			if foundScore {
+				return nil, fmt.Errorf("second score on line %d", line)
+			}
+
+			score, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid score on line %d: %w", line, err)
+			}
+
+			rp.Score = score
+			foundScore = true
+
+			continue
+		}
+
+		frame, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid frame on line %d: %w", line, err)
+		}
+
+		joystick, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid joystick on line %d: %w", line, err)
+		}
+
+		// Every frame needs an input so they must follow each other.
+		if frame != len(rp.Inputs) {
+			return nil, fmt.Errorf("expected frame %d on line %d, got %d", len(rp.Inputs), line, frame)
+		}
+
+		switch joystick {
+		case joystickLeft, joystickNeutral, joytickRight:
+		default:
+			return nil, fmt.Errorf("invalid joystick %d on line %d", joystick, line)
+		}
+
+		rp.Inputs = append(rp.Inputs, replayInput{Frame: frame, Joystick: joystick})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if !foundScore {
+		return nil, errors.New("missing score")
+	}
+
+	return &rp, nil
+}
+
+func (rp *replay) write(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(f)
+
+	fmt.Fprintf(bw, "score %d\n", rp.Score)
+
+	for _, input := range rp.Inputs {
+		fmt.Fprintf(bw, "%d %d\n", input.Frame, input.Joystick)
+	}
+
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"advent.of.code/grid"
+	"advent.of.code/intcode"
+)
+
+// snapshot holds everything needed to resume a game: the memory and registers
+// of the computer waiting for input and the state of the game drawn so far.
+type snapshot struct {
+	Sequence       []int
+	Pointer        int
+	Base           int
+	Tiles          []snapshotTile
+	Objects        map[int]int
+	Score          int
+	Frame          int
+	PaddleMoves    int
+	BallPosition   grid.Point
+	BallVelocity   grid.Point
+	PaddlePosition grid.Point
+}
+
+// snapshotTile is a tile drawn on the screen, saved as a list since JSON
+// can't have points as keys.
+type snapshotTile struct {
+	Point grid.Point
+	Tile  int
+}
+
+// saver saves a snapshot at a given frame or when requested by the player
+// before asking another controller for the joystick input.
+type saver struct {
+	Controller controller
+	Computer   *intcode.Computer
+	Filename   string
+	Frame      int
+}
+
+func (s saver) joystick(g *game) int {
+	joystick := s.Controller.joystick(g)
+
+	// The computer hasn't been given the input yet so it's still waiting at
+	// the same instruction.
+	if g.Frame == s.Frame || g.SaveRequested {
+		g.SaveRequested = false
+
+		if err := newSnapshot(s.Computer, g).write(s.Filename); err != nil {
+			fmt.Fprintf(g.Out, "could not save game: %s\n", err.Error())
+		} else {
+			fmt.Fprintf(g.Out, "saved frame %d to %s\n", g.Frame, s.Filename)
+		}
+	}
+
+	return joystick
+}
+
+func newSnapshot(c *intcode.Computer, g *game) *snapshot {
+	s := snapshot{
+		Sequence:       make([]int, len(c.Sequence)),
+		Pointer:        c.Pointer,
+		Base:           c.Base,
+		Tiles:          make([]snapshotTile, 0, len(g.Grid)),
+		Objects:        map[int]int{},
+		Score:          g.Display,
+		Frame:          g.Frame,
+		PaddleMoves:    g.PaddleMoves,
+		BallPosition:   g.BallPosition,
+		BallVelocity:   g.BallVelocity,
+		PaddlePosition: g.PaddlePosition,
+	}
+
+	copy(s.Sequence, c.Sequence)
+
+	for p, tile := range g.Grid {
+		s.Tiles = append(s.Tiles, snapshotTile{Point: p, Tile: tile})
+	}
+
+	for k, v := range g.Objects {
+		s.Objects[k] = v
+	}
+
+	return &s
+}
+
+func readSnapshot(filename string) (*snapshot, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var s snapshot
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("invalid snapshot in %s: %w", filename, err)
+	}
+
+	return &s, nil
+}
+
+// validate returns an error if the snapshot can't be resumed, e.g. if it's
+// truncated or edited by hand.
+func (s *snapshot) validate() error {
+	if s.Pointer < 0 || s.Pointer >= len(s.Sequence) {
+		return fmt.Errorf("pointer %d outside of the %d values in memory", s.Pointer, len(s.Sequence))
+	}
+
+	if len(s.Tiles) == 0 {
+		return errors.New("no tiles drawn")
+	}
+
+	for _, t := range s.Tiles {
+		if t.Point.X < 0 || t.Point.Y < 0 {
+			return fmt.Errorf("tile outside of the screen at %d,%d", t.Point.X, t.Point.Y)
+		}
+
+		if _, ok := tileMap[t.Tile]; !ok {
+			return fmt.Errorf("unknown tile %d at %d,%d", t.Tile, t.Point.X, t.Point.Y)
+		}
+	}
+
+	if s.Objects == nil {
+		s.Objects = map[int]int{}
+	}
+
+	return nil
+}
+
+func (s *snapshot) write(filename string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+// restore puts the computer and game back in the state of the snapshot.
+func (s *snapshot) restore(c *intcode.Computer, g *game) {
+	c.Sequence = s.Sequence
+	c.Pointer = s.Pointer
+	c.Base = s.Base
+
+	g.Grid = grid.Grid{}
+
+	for _, t := range s.Tiles {
+		g.Grid[t.Point] = t.Tile
+	}
+
+	g.Objects = s.Objects
+	g.Display = s.Score
+	g.Frame = s.Frame
+	g.PaddleMoves = s.PaddleMoves
+	g.BallPosition = s.BallPosition
+	g.PrevBallPosition = s.BallPosition
+	g.BallVelocity = s.BallVelocity
+	g.PaddlePosition = s.PaddlePosition
+}
@@ -2,4 +2,15 @@ module advent.of.code/15
 
 go 1.13
 
-require github.com/davecgh/go-spew v1.1.1
+require (
+	advent.of.code/grid v0.0.0
+	advent.of.code/intcode v0.0.0
+	advent.of.code/pathfind v0.0.0
+	github.com/davecgh/go-spew v1.1.1
+)
+
+replace (
+	advent.of.code/grid => ../../grid/go
+	advent.of.code/intcode => ../../intcode/go
+	advent.of.code/pathfind => ../../pathfind/go
+)
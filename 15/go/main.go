@@ -1,60 +1,23 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"log"
 	"os"
-	"strconv"
-	"strings"
-)
+	"time"
 
-// * ParamModePosition  -> Use the value found at slice[n]
-// * ParamModeImmediate -> Use the value n
-// * ParamModeRelative  -> Use the value found at slice[BASE+n]
-const (
-	paramModePosition = iota
-	paramModeImmediate
-	paramModeRelative
+	"advent.of.code/grid"
+	"advent.of.code/intcode"
+	"advent.of.code/pathfind"
 )
 
 const (
-	opCodeAdd         = 1
-	opCodeMultiply    = 2
-	opCodeStore       = 3
-	opCodeOutput      = 4
-	opCodeJumpIfTrue  = 5
-	opCodeJumpIfFalse = 6
-	opCodeLessThan    = 7
-	opCodeEquals      = 8
-	opCodeAdjustBase  = 9
-	opCodeHalt        = 99
-)
-
-// nolint: gochecknoglobals
-var jumpMap = map[int]int{
-	opCodeAdd:         4,
-	opCodeMultiply:    4,
-	opCodeStore:       2,
-	opCodeOutput:      2,
-	opCodeJumpIfTrue:  3,
-	opCodeJumpIfFalse: 3,
-	opCodeLessThan:    4,
-	opCodeEquals:      4,
-	opCodeAdjustBase:  2,
-}
-
-type computer struct {
-	Input         int
-	Output        []int
-	Pointer       int
-	Base          int
-	Sequence      []int
-	Halted        bool
-	PauseAtOutput bool
-}
-
-const (
-	gridSize = 50
+	tileWall = iota
+	tilePath
+	tileOxygen
 )
 
 const (
@@ -63,11 +26,15 @@ const (
 	unknown   = "🧱"
 	oxygen    = "💧"
 	robotChar = "🤖"
+
+	frontierChar = "🔸"
 )
 
-type coordinate struct {
-	X int
-	Y int
+// nolint: gochecknoglobals
+var tileMap = map[int]string{
+	tileWall:   wall,
+	tilePath:   path,
+	tileOxygen: oxygen,
 }
 
 type direction int
@@ -79,15 +46,22 @@ const (
 	directionEast
 )
 
+// nolint: gochecknoglobals
+var directions = []direction{directionNorth, directionSouth, directionWest, directionEast}
+
+// robot represents the repair droid. It explores the area by moving to every
+// neighbouring position not yet known and backtracks the way it came when
+// there are none left.
 type robot struct {
-	X             int
-	Y             int
-	Direction     direction
-	Grid          map[coordinate]string
-	Computer      computer
-	oxygenPos     coordinate
-	oxygenStep    int
-	oxygenMaxStep int
+	Position     grid.Point
+	Direction    direction
+	Grid         grid.Grid
+	Path         []direction
+	Watcher      *watcher
+	backtracking bool
+	start        grid.Point
+	oxygenPos    grid.Point
+	oxygenFound  bool
 }
 
 func (d direction) String() string {
@@ -105,267 +79,342 @@ func (d direction) String() string {
 	return "unknown"
 }
 
-func newRobot(sequence []int) *robot {
+func (d direction) opposite() direction {
+	switch d {
+	case directionNorth:
+		return directionSouth
+	case directionSouth:
+		return directionNorth
+	case directionWest:
+		return directionEast
+	case directionEast:
+		return directionWest
+	}
+
+	return d
+}
+
+// heading returns the heading on the grid to move in the direction.
+func (d direction) heading() grid.Heading {
+	switch d {
+	case directionNorth:
+		return grid.Up
+	case directionSouth:
+		return grid.Down
+	case directionWest:
+		return grid.Left
+	case directionEast:
+		return grid.Right
+	}
+
+	return grid.Heading{}
+}
+
+func newRobot() *robot {
 	r := robot{
-		X:    gridSize / 2,
-		Y:    gridSize / 2,
-		Grid: map[coordinate]string{},
-		Computer: computer{
-			Sequence:      make([]int, len(sequence)),
-			PauseAtOutput: true,
-			Input:         directionNorth,
-		},
+		Grid: grid.Grid{},
 	}
 
-	copy(r.Computer.Sequence, sequence)
+	// Mark the start position as a path.
+	r.start = r.Position
+	r.Grid[r.start] = tilePath
 
 	return &r
 }
 
 func main() {
 	var (
-		line, _        = ioutil.ReadFile(os.Args[1])
-		stringSequence = strings.Split(string(line), ",")
-		sequence       = make([]int, len(stringSequence))
+		showFrames = flag.Bool("frames", false, "show the maze every minute while the oxygen spreads")
+		saveMap    = flag.String("save-map", "", "save the mapped maze to this file")
+		loadMap    = flag.String("load-map", "", "load the maze from this file instead of running the program")
+		watch      = flag.Bool("watch", false, "animate the droid exploring the maze and the oxygen spreading")
+		watchDelay = flag.Duration("watch-delay", 20*time.Millisecond, "delay after each frame when watching")
 	)
 
-	for i := range sequence {
-		sequence[i], _ = strconv.Atoi(strings.TrimSpace(stringSequence[i]))
-	}
+	flag.Parse()
 
-	r1 := newRobot(sequence)
-	r1.checkNext(sequence, 1)
+	var (
+		r1      *robot
+		watcher *watcher
+	)
 
-	r1.show()
+	if *watch {
+		watcher = newWatcher(os.Stdout, *watchDelay)
+	}
 
-	// Put the robot at the oxygen position after completing the first part.
-	r1.X, r1.Y = r1.oxygenPos.X, r1.oxygenPos.Y
-	r1.oxygenTime(make(map[coordinate]struct{}), 1)
+	if *loadMap != "" {
+		r, err := readMap(*loadMap)
+		if err != nil {
+			log.Fatalf("could not load map: %s", err.Error())
+		}
 
-	fmt.Println("part 1: found oxygen after", r1.oxygenStep, "steps")
-	fmt.Println("part 2: time to fill", r1.oxygenMaxStep)
-}
+		r1 = r
+	} else {
+		if flag.NArg() < 1 {
+			log.Fatal("missing file as input")
+		}
 
-func (r *robot) checkNext(originalSequence []int, stepsFromStart int) {
-	var (
-		x   = r.X
-		y   = r.Y
-		seq = make([]int, len(originalSequence))
-	)
+		r1 = explore(flag.Arg(0), watcher)
+	}
 
-	// Copy the original sequence passed to this function to sequence.
-	copy(seq, originalSequence)
+	if watcher == nil {
+		r1.show()
+	}
 
-	// Mark the current position as a path.
-	r.Grid[coordinate{X: x, Y: y}] = path
+	if *saveMap != "" {
+		if err := r1.writeMap(*saveMap); err != nil {
+			log.Fatalf("could not save map: %s", err.Error())
+		}
+	}
 
-	for _, dir := range []direction{directionNorth, directionSouth, directionWest, directionEast} {
-		// Copy the sequence passed so we can reset it for each direction.
-		copy(r.Computer.Sequence, seq)
+	path, ok := r1.shortestPath(r1.start, r1.oxygenPos)
+	if !ok {
+		log.Fatal("could not find a path to the oxygen system")
+	}
 
-		// Reset X and Y for each direction based on where we started.
-		r.X = x
-		r.Y = y
+	steps := len(path) - 1
 
-		// Set the new direction (just for info) and set the direction as input
-		// to the computer.
-		r.Direction = dir
-		r.Computer.Input = int(dir)
+	var frame func(int, map[grid.Point]struct{})
 
-		// Get the new coordinates based of the direction we're looking. If
-		// we've already been in that direction, move on.
-		nextCoordinates := r.nextCoordiantes()
-		if _, ok := r.Grid[nextCoordinates]; ok {
-			continue
+	switch {
+	case watcher != nil:
+		frame = func(minute int, filled map[grid.Point]struct{}) {
+			watcher.draw(r1, filled, fmt.Sprintf("minute %d", minute))
 		}
+	case *showFrames:
+		frame = func(minute int, filled map[grid.Point]struct{}) {
+			fmt.Println("minute", minute)
+			r1.render(os.Stdout, filled, false)
+		}
+	}
 
-		// Run one clock in the computer.
-		r.Computer.process()
+	minutes := r1.spreadOxygen([]grid.Point{r1.oxygenPos}, frame)
 
-		// Fetch the output from the process.
-		moveResult := r.Computer.Output[0]
-		r.Computer.Output = []int{}
+	fmt.Println("part 1: found oxygen after", steps, "steps")
+	fmt.Println("part 2: time to fill", minutes)
+}
 
-		switch moveResult {
-		case 0:
-			r.Grid[nextCoordinates] = wall
+// explore runs the program in the file and maps the maze with the droid. Every
+// move is drawn if a watcher is given.
+func explore(filename string, w *watcher) *robot {
+	line, err := ioutil.ReadFile(filename)
+	if err != nil {
+		log.Fatalf("could not read file: %s", err.Error())
+	}
 
-		case 1:
-			r.Grid[nextCoordinates] = path
-			r.X = nextCoordinates.X
-			r.Y = nextCoordinates.Y
+	sequence, err := intcode.Parse(line)
+	if err != nil {
+		log.Fatalf("could not parse program: %s", err.Error())
+	}
 
-			// Keep going this direction
-			r.checkNext(r.Computer.Sequence, stepsFromStart+1)
+	r := newRobot()
+	r.Watcher = w
 
-		case 2:
-			r.oxygenPos = nextCoordinates
-			r.oxygenStep = stepsFromStart
-			r.Grid[nextCoordinates] = oxygen
-		}
+	if err := intcode.Run(intcode.New(sequence), r); err != nil {
+		log.Fatal(err)
+	}
+
+	if !r.oxygenFound {
+		log.Fatal("could not find the oxygen system")
 	}
-}
 
-func (r *robot) oxygenTime(seen map[coordinate]struct{}, maxSteps int) {
-	x, y := r.X, r.Y
+	return r
+}
 
-	for _, dir := range []direction{directionNorth, directionSouth, directionWest, directionEast} {
+// Observe returns the direction to move. The robot moves in the first
+// direction not yet known or, if every neighbour is known, back the way it
+// came.
+func (r *robot) Observe() []int {
+	if dir, ok := r.unexplored(); ok {
 		r.Direction = dir
-		r.X, r.Y = x, y
+		r.backtracking = false
 
-		nextCoordinates := r.nextCoordiantes()
-		if _, ok := seen[nextCoordinates]; ok {
-			continue
-		}
+		return []int{int(dir)}
+	}
 
-		typ, ok := r.Grid[nextCoordinates]
-		if !ok {
-			continue
-		}
+	r.Direction = r.Path[len(r.Path)-1].opposite()
+	r.backtracking = true
 
-		if typ != path {
-			continue
-		}
+	return []int{int(r.Direction)}
+}
+
+// Consume updates the grid with the result of the last move.
+func (r *robot) Consume(output []int) error {
+	if r.Watcher != nil {
+		defer r.Watcher.draw(r, nil, fmt.Sprintf("exploring, %d steps from start", len(r.Path)))
+	}
+
+	var (
+		moveResult = output[0]
+		next       = r.next(r.Direction)
+	)
 
-		if maxSteps > r.oxygenMaxStep {
-			r.oxygenMaxStep = maxSteps
+	if r.backtracking {
+		if moveResult == 0 {
+			return fmt.Errorf("could not move back %s from %d,%d", r.Direction, r.Position.X, r.Position.Y)
 		}
 
-		seen[nextCoordinates] = struct{}{}
-		r.X = nextCoordinates.X
-		r.Y = nextCoordinates.Y
+		r.Position = next
+		r.Path = r.Path[:len(r.Path)-1]
 
-		r.oxygenTime(seen, maxSteps+1)
+		return nil
 	}
+
+	switch moveResult {
+	case 0:
+		r.Grid[next] = tileWall
+
+	case 1:
+		r.Grid[next] = tilePath
+		r.Position = next
+		r.Path = append(r.Path, r.Direction)
+
+	case 2:
+		r.Grid[next] = tileOxygen
+		r.Position = next
+		r.Path = append(r.Path, r.Direction)
+		r.oxygenPos = next
+		r.oxygenFound = true
+
+	default:
+		return fmt.Errorf("unknown move result %d", moveResult)
+	}
+
+	return nil
 }
 
-func (r *robot) show() {
-	for x := range make([]struct{}, gridSize) {
-		for y := range make([]struct{}, gridSize) {
-			c := coordinate{X: x, Y: y}
-			p := unknown
+// OutputSize returns one since the program outputs the result of each move.
+func (r *robot) OutputSize() int {
+	return 1
+}
 
-			if v, ok := r.Grid[c]; ok {
-				p = v
-			}
+// Done returns true when the robot is back at the start with every position
+// reachable known.
+func (r *robot) Done() bool {
+	_, ok := r.unexplored()
 
-			if x == r.X && y == r.Y {
-				p = robotChar
-			}
+	return !ok && len(r.Path) == 0
+}
 
-			fmt.Print(p)
+// unexplored returns the first direction from the current position that's not
+// yet known.
+func (r *robot) unexplored() (direction, bool) {
+	for _, dir := range directions {
+		if _, ok := r.Grid[r.next(dir)]; !ok {
+			return dir, true
 		}
-
-		fmt.Println("")
 	}
+
+	return 0, false
 }
 
-func (r *robot) nextCoordiantes() coordinate {
-	x, y := r.X, r.Y
+// maze returns the mapped grid as a graph where every known position that's
+// not a wall can be passed.
+func (r *robot) maze() pathfind.Grid {
+	return pathfind.Grid{Passable: r.open}
+}
 
-	switch r.Direction {
-	case directionNorth:
-		x--
-	case directionSouth:
-		x++
-	case directionWest:
-		y--
-	case directionEast:
-		y++
-	}
+// shortestPath returns the positions on the shortest path between from and to,
+// including both, found with A* so it's the shortest even if the maze has
+// loops.
+func (r *robot) shortestPath(from, to grid.Point) ([]pathfind.Node, bool) {
+	path, _, ok := pathfind.AStar(r.maze(), from, to, func(n pathfind.Node) int {
+		return grid.Manhattan(n.(grid.Point), to)
+	})
 
-	return coordinate{X: x, Y: y}
+	return path, ok
 }
 
-func (c *computer) process() {
-	opCode := c.Sequence[c.Pointer] % 100
+// spreadOxygen fills the maze with oxygen from the given sources, spreading to
+// every neighbouring open position each minute, and returns the number of
+// minutes until every reachable position is filled. If frame is set it's
+// called with every position filled after each minute, starting with the
+// sources at minute zero.
+func (r *robot) spreadOxygen(sources []grid.Point, frame func(minute int, filled map[grid.Point]struct{})) int {
+	var (
+		starts = make([]pathfind.Node, len(sources))
+		byTime = map[int][]grid.Point{}
+		filled = map[grid.Point]struct{}{}
+	)
 
-	// Halt code found, stop processing.
-	if opCode == opCodeHalt {
-		c.Halted = true
-		return
+	for i, c := range sources {
+		starts[i] = c
 	}
 
-	getPointer := func(argumentPosition int) int {
-		var (
-			pointer       = 0
-			modePositions = c.Sequence[c.Pointer] / 100
-			positions     = map[int]int{
-				1: modePositions % 10,
-				2: modePositions % 100 / 10,
-				3: modePositions % 1000 / 100,
-			}
-		)
-
-		switch positions[argumentPosition] {
-		case paramModePosition:
-			pointer = c.Sequence[c.Pointer+argumentPosition]
-		case paramModeImmediate:
-			pointer = c.Pointer + argumentPosition
-		case paramModeRelative:
-			pointer = c.Sequence[c.Pointer+argumentPosition] + c.Base
-		}
-
-		if pointer >= len(c.Sequence) {
-			c.Sequence = append(c.Sequence, make([]int, pointer-len(c.Sequence)+1)...)
-		}
+	// The oxygen reaches every position the minute equal to the distance
+	// from the closest source.
+	result := pathfind.BFS(r.maze(), starts...)
 
-		return pointer
+	if frame == nil {
+		return result.Max()
 	}
 
-	sequenceFor := func(pos int) int {
-		return c.Sequence[getPointer(pos)]
+	for n, d := range result.Dist {
+		byTime[d] = append(byTime[d], n.(grid.Point))
 	}
 
-	switch opCode {
-	case opCodeAdd:
-		c.Sequence[getPointer(3)] = sequenceFor(1) + sequenceFor(2)
+	for minute := 0; minute <= result.Max(); minute++ {
+		for _, c := range byTime[minute] {
+			filled[c] = struct{}{}
+		}
+
+		frame(minute, filled)
+	}
 
-	case opCodeMultiply:
-		c.Sequence[getPointer(3)] = sequenceFor(1) * sequenceFor(2)
+	return result.Max()
+}
 
-	case opCodeStore:
-		c.Sequence[getPointer(1)] = c.Input
+func (r *robot) show() {
+	r.render(os.Stdout, nil, false)
+}
 
-	case opCodeOutput:
-		c.Output = append(c.Output, sequenceFor(1))
+// render writes the grid with every position in filled as oxygen. If frontier
+// is set every open position next to one not yet known is highlighted.
+func (r *robot) render(w io.Writer, filled map[grid.Point]struct{}, frontier bool) {
+	tiles := r.Grid.Glyphs(tileMap, unknown)
 
-		if c.PauseAtOutput {
-			c.Pointer += jumpMap[opCodeOutput]
-			return
+	_ = grid.Render(w, r.bounds(), func(p grid.Point) string {
+		if p == r.Position {
+			return robotChar
 		}
 
-	case opCodeJumpIfTrue:
-		if c.Sequence[getPointer(1)] != 0 {
-			c.Pointer = sequenceFor(2) - jumpMap[opCodeJumpIfTrue]
+		if _, ok := filled[p]; ok {
+			return oxygen
 		}
 
-	case opCodeJumpIfFalse:
-		if c.Sequence[getPointer(1)] == 0 {
-			c.Pointer = sequenceFor(2) - jumpMap[opCodeJumpIfFalse]
+		if frontier && r.Grid[p] == tilePath && r.unknownNeighbour(p) {
+			return frontierChar
 		}
 
-	case opCodeLessThan:
-		if c.Sequence[getPointer(1)] < c.Sequence[getPointer(2)] {
-			c.Sequence[getPointer(3)] = 1
-		} else {
-			c.Sequence[getPointer(3)] = 0
-		}
+		return tiles(p)
+	})
+}
 
-	case opCodeEquals:
-		if c.Sequence[getPointer(1)] == c.Sequence[getPointer(2)] {
-			c.Sequence[getPointer(3)] = 1
-		} else {
-			c.Sequence[getPointer(3)] = 0
+// unknownNeighbour returns true if any position next to p isn't known yet.
+func (r *robot) unknownNeighbour(p grid.Point) bool {
+	for _, n := range p.Neighbours() {
+		if _, ok := r.Grid[n]; !ok {
+			return true
 		}
+	}
 
-	case opCodeAdjustBase:
-		c.Base += sequenceFor(1)
+	return false
+}
 
-	default:
-		panic(fmt.Sprintf("unknown instruction at position %d, opCode: %d", c.Pointer, opCode))
-	}
+// open returns true if the position is known and not a wall.
+func (r *robot) open(p grid.Point) bool {
+	typ, ok := r.Grid[p]
+
+	return ok && typ != tileWall
+}
+
+// bounds returns the rectangle containing every position known or visited by
+// the robot.
+func (r *robot) bounds() grid.Rect {
+	return r.Grid.Bounds(r.Position)
+}
 
-	c.Pointer += jumpMap[opCode]
-	c.process()
+// next returns the position one step from the robot in the direction.
+func (r *robot) next(d direction) grid.Point {
+	return r.Position.Move(d.heading(), 1)
 }
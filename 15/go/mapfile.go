@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"advent.of.code/grid"
+)
+
+// Characters used for each position when the maze is stored as text.
+const (
+	mapWall    = '#'
+	mapOpen    = '.'
+	mapOxygen  = 'O'
+	mapDroid   = 'D'
+	mapUnknown = ' '
+)
+
+// writeMap writes the maze as text with one row per line. The droid is written
+// at the position it started from.
+func (r *robot) writeMap(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	var (
+		bw     = bufio.NewWriter(f)
+		bounds = r.bounds()
+	)
+
+	for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+		row := make([]rune, 0, bounds.Width())
+
+		for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+			c := grid.Point{X: x, Y: y}
+
+			switch typ, ok := r.Grid[c]; {
+			case !ok:
+				row = append(row, mapUnknown)
+			case c == r.start:
+				row = append(row, mapDroid)
+			case typ == tileWall:
+				row = append(row, mapWall)
+			case typ == tileOxygen:
+				row = append(row, mapOxygen)
+			default:
+				row = append(row, mapOpen)
+			}
+		}
+
+		fmt.Fprintln(bw, strings.TrimRight(string(row), string(mapUnknown)))
+	}
+
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// readMap reads a maze written by writeMap and returns a robot standing at the
+// droid position with the whole maze known.
+func readMap(filename string) (*robot, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var (
+		r = &robot{Grid: grid.Grid{}}
+
+		scanner             = bufio.NewScanner(f)
+		foundDroid, foundO2 bool
+	)
+
+	for y := 0; scanner.Scan(); y++ {
+		for x, char := range []rune(scanner.Text()) {
+			c := grid.Point{X: x, Y: y}
+
+			switch char {
+			case mapUnknown:
+				continue
+			case mapWall:
+				r.Grid[c] = tileWall
+			case mapOpen:
+				r.Grid[c] = tilePath
+			case mapOxygen:
+				r.Grid[c] = tileOxygen
+				r.oxygenPos = c
+				foundO2 = true
+			case mapDroid:
+				r.Grid[c] = tilePath
+				r.start = c
+				r.Position = c
+				foundDroid = true
+			default:
+				return nil, fmt.Errorf("unknown character '%c' at %d,%d", char, x, y)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if !foundDroid {
+		return nil, fmt.Errorf("no droid (%c) in map", mapDroid)
+	}
+
+	if !foundO2 {
+		return nil, fmt.Errorf("no oxygen system (%c) in map", mapOxygen)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"advent.of.code/grid"
+)
+
+// watcher redraws the maze in place for every frame, first while the droid
+// explores it and then while the oxygen spreads.
+type watcher struct {
+	Out    io.Writer
+	Delay  time.Duration
+	screen grid.Screen
+}
+
+func newWatcher(w io.Writer, delay time.Duration) *watcher {
+	return &watcher{Out: w, Delay: delay}
+}
+
+// draw redraws the maze with a status line below it. The frontier is only
+// shown until the oxygen starts spreading.
+func (w *watcher) draw(r *robot, filled map[grid.Point]struct{}, status string) {
+	_ = w.screen.Draw(w.Out, func(out io.Writer) {
+		r.render(out, filled, filled == nil)
+		fmt.Fprintln(out, status)
+	})
+
+	time.Sleep(w.Delay)
+}
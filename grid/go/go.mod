@@ -0,0 +1,3 @@
+module advent.of.code/grid
+
+go 1.13
@@ -0,0 +1,142 @@
+package grid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Escape codes used by Screen to redraw the terminal in place.
+const (
+	ansiClearScreen = "\033[H\033[2J"
+	ansiCursorHome  = "\033[H"
+	ansiClearBelow  = "\033[J"
+)
+
+// Grid is a sparse grid holding a value for every known point.
+type Grid map[Point]int
+
+// Rect represents a rectangle including both Min and Max.
+type Rect struct {
+	Min Point
+	Max Point
+}
+
+// Screen redraws frames in place on a terminal with ANSI escape codes. The
+// screen is cleared before the first frame.
+type Screen struct {
+	drawn bool
+}
+
+// Bounds returns the smallest rectangle containing every point in the grid and
+// every point given. The zero Rect is returned if there are no points at all.
+func (g Grid) Bounds(include ...Point) Rect {
+	var (
+		r     = Rect{}
+		first = true
+	)
+
+	extend := func(p Point) {
+		if first {
+			r = Rect{Min: p, Max: p}
+			first = false
+
+			return
+		}
+
+		r = r.Extend(p)
+	}
+
+	for _, p := range include {
+		extend(p)
+	}
+
+	for p := range g {
+		extend(p)
+	}
+
+	return r
+}
+
+// Extend returns the smallest rectangle containing both r and p.
+func (r Rect) Extend(p Point) Rect {
+	if p.X < r.Min.X {
+		r.Min.X = p.X
+	}
+
+	if p.Y < r.Min.Y {
+		r.Min.Y = p.Y
+	}
+
+	if p.X > r.Max.X {
+		r.Max.X = p.X
+	}
+
+	if p.Y > r.Max.Y {
+		r.Max.Y = p.Y
+	}
+
+	return r
+}
+
+// Contains returns true if p is inside the rectangle.
+func (r Rect) Contains(p Point) bool {
+	return p.X >= r.Min.X && p.X <= r.Max.X && p.Y >= r.Min.Y && p.Y <= r.Max.Y
+}
+
+// Width returns the number of columns in the rectangle.
+func (r Rect) Width() int {
+	return r.Max.X - r.Min.X + 1
+}
+
+// Height returns the number of rows in the rectangle.
+func (r Rect) Height() int {
+	return r.Max.Y - r.Min.Y + 1
+}
+
+// Render writes every point in the rectangle, one row per line, as the string
+// returned by cell.
+func Render(w io.Writer, r Rect, cell func(p Point) string) error {
+	bw := bufio.NewWriter(w)
+
+	for y := r.Min.Y; y <= r.Max.Y; y++ {
+		for x := r.Min.X; x <= r.Max.X; x++ {
+			fmt.Fprint(bw, cell(Point{X: x, Y: y}))
+		}
+
+		fmt.Fprintln(bw, "")
+	}
+
+	return bw.Flush()
+}
+
+// Glyphs returns a cell function for Render showing every value in the grid
+// with its glyph and every unknown point as unknown.
+func (g Grid) Glyphs(glyphs map[int]string, unknown string) func(p Point) string {
+	return func(p Point) string {
+		v, ok := g[p]
+		if !ok {
+			return unknown
+		}
+
+		return glyphs[v]
+	}
+}
+
+// Draw redraws the screen written to w with whatever draw writes.
+func (s *Screen) Draw(w io.Writer, draw func(w io.Writer)) error {
+	bw := bufio.NewWriter(w)
+
+	if s.drawn {
+		fmt.Fprint(bw, ansiCursorHome)
+	} else {
+		fmt.Fprint(bw, ansiClearScreen)
+		s.drawn = true
+	}
+
+	draw(bw)
+
+	fmt.Fprint(bw, ansiClearBelow)
+
+	return bw.Flush()
+}
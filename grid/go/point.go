@@ -0,0 +1,100 @@
+// Package grid implements points, headings and a sparse grid with X growing to
+// the right and Y growing downwards.
+package grid
+
+// Point represents a position on a grid.
+type Point struct {
+	X int
+	Y int
+}
+
+// Heading represents a direction as the vector moved for each step.
+type Heading struct {
+	X int
+	Y int
+}
+
+// The four headings in clockwise order.
+// nolint: gochecknoglobals
+var (
+	Up    = Heading{X: 0, Y: -1}
+	Right = Heading{X: 1, Y: 0}
+	Down  = Heading{X: 0, Y: 1}
+	Left  = Heading{X: -1, Y: 0}
+
+	Headings = []Heading{Up, Right, Down, Left}
+)
+
+// Add returns the sum of both points.
+func (p Point) Add(q Point) Point {
+	return Point{X: p.X + q.X, Y: p.Y + q.Y}
+}
+
+// Sub returns the difference between both points.
+func (p Point) Sub(q Point) Point {
+	return Point{X: p.X - q.X, Y: p.Y - q.Y}
+}
+
+// Move returns the point the given number of steps in the heading.
+func (p Point) Move(h Heading, steps int) Point {
+	return Point{X: p.X + h.X*steps, Y: p.Y + h.Y*steps}
+}
+
+// Neighbours returns the four points next to p in the order of Headings.
+func (p Point) Neighbours() []Point {
+	neighbours := make([]Point, len(Headings))
+
+	for i, h := range Headings {
+		neighbours[i] = p.Move(h, 1)
+	}
+
+	return neighbours
+}
+
+// Manhattan returns the Manhattan distance from the origin.
+func (p Point) Manhattan() int {
+	return Manhattan(p, Point{})
+}
+
+// Manhattan returns the Manhattan distance between both points.
+func Manhattan(a, b Point) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+// RotateLeft returns the heading turned 90 degrees counterclockwise.
+func (h Heading) RotateLeft() Heading {
+	return Heading{X: h.Y, Y: -h.X}
+}
+
+// RotateRight returns the heading turned 90 degrees clockwise.
+func (h Heading) RotateRight() Heading {
+	return Heading{X: -h.Y, Y: h.X}
+}
+
+// Reverse returns the opposite heading.
+func (h Heading) Reverse() Heading {
+	return Heading{X: -h.X, Y: -h.Y}
+}
+
+func (h Heading) String() string {
+	switch h {
+	case Up:
+		return "^"
+	case Right:
+		return ">"
+	case Down:
+		return "v"
+	case Left:
+		return "<"
+	}
+
+	return "O"
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
@@ -0,0 +1,67 @@
+package intcode
+
+import (
+	"errors"
+)
+
+// ErrNoInput is returned when the environment has no input to give a program
+// waiting for it.
+var ErrNoInput = errors.New("environment gave no input")
+
+// Environment is something an Intcode program interacts with, e.g. a robot
+// moving around or an arcade cabinet. The environment is observed whenever the
+// program needs input and consumes the output in groups of OutputSize words.
+type Environment interface {
+	// Observe returns the words to give the program when it needs input.
+	Observe() []int
+
+	// Consume updates the environment with a group of output words.
+	Consume(output []int) error
+
+	// OutputSize returns the number of words in each group of output.
+	OutputSize() int
+
+	// Done returns true if the environment wants to stop the program before
+	// it halts.
+	Done() bool
+}
+
+// Run drives the program on the computer against the environment until the
+// program halts or the environment is done.
+func Run(c *Computer, env Environment) error {
+	var (
+		size = env.OutputSize()
+	)
+
+	c.PauseAtOutput = true
+
+	for !env.Done() {
+		if err := c.Process(); err != nil {
+			return err
+		}
+
+		for size > 0 && len(c.Output) >= size {
+			output := c.Output[:size]
+			c.Output = c.Output[size:]
+
+			if err := env.Consume(output); err != nil {
+				return err
+			}
+		}
+
+		if c.Halted {
+			return nil
+		}
+
+		if c.Waiting {
+			input := env.Observe()
+			if len(input) == 0 {
+				return ErrNoInput
+			}
+
+			c.Input = append(c.Input, input...)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,297 @@
+package intcode
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Block represents a basic block, a list of instructions only entered at the
+// first one and only left at the last one.
+type Block struct {
+	Start        int
+	End          int
+	Instructions []Instruction
+	Successors   []int
+	ReturnSites  []int
+	Unknown      bool
+	Halts        bool
+	Invalid      bool
+}
+
+// Graph represents the control flow graph found by statically walking a
+// program from address 0.
+type Graph struct {
+	Sequence []int
+	Blocks   map[int]*Block
+	Code     map[int]struct{}
+	starts   map[int]struct{}
+}
+
+// ControlFlow walks the program from address 0 and builds a control flow
+// graph. Both branches of a conditional jump are followed when the target is
+// immediate, jumps with a target read from memory are recorded as unknown.
+//
+// Functions in Intcode are called by pushing the return address to the stack
+// with an instruction like `21101,0,37,0` before jumping. Such constants are
+// treated as return sites and explored as well since they can't be found by
+// following the jumps.
+func ControlFlow(sequence []int) *Graph {
+	var (
+		g = &Graph{
+			Sequence: sequence,
+			Blocks:   map[int]*Block{},
+			Code:     map[int]struct{}{},
+			starts:   map[int]struct{}{},
+		}
+		leaders = map[int]struct{}{0: {}}
+		queue   = []int{0}
+	)
+
+	addLeader := func(address int) {
+		if _, ok := leaders[address]; ok {
+			return
+		}
+
+		leaders[address] = struct{}{}
+		queue = append(queue, address)
+	}
+
+	for len(queue) > 0 {
+		address := queue[0]
+		queue = queue[1:]
+
+		for g.inRange(address) {
+			if _, ok := g.starts[address]; ok {
+				break
+			}
+
+			instruction := Decode(sequence, address)
+			g.starts[address] = struct{}{}
+
+			if !Valid(instruction.OpCode) {
+				break
+			}
+
+			for i := address; i < address+instruction.Length(); i++ {
+				g.Code[i] = struct{}{}
+			}
+
+			targets, fallsThrough, _ := flow(instruction)
+
+			for _, t := range targets {
+				addLeader(t)
+			}
+
+			if site, ok := returnSite(instruction); ok && g.inRange(site) {
+				addLeader(site)
+			}
+
+			if !fallsThrough {
+				break
+			}
+
+			address += instruction.Length()
+
+			if isJump(instruction.OpCode) {
+				addLeader(address)
+			}
+		}
+	}
+
+	for leader := range leaders {
+		if g.inRange(leader) {
+			g.Blocks[leader] = g.block(leader, leaders)
+		}
+	}
+
+	return g
+}
+
+func (g *Graph) block(start int, leaders map[int]struct{}) *Block {
+	var (
+		b       = &Block{Start: start}
+		address = start
+	)
+
+	for g.inRange(address) {
+		instruction := Decode(g.Sequence, address)
+		b.Instructions = append(b.Instructions, instruction)
+		address += instruction.Length()
+
+		if site, ok := returnSite(instruction); ok && g.inRange(site) {
+			b.ReturnSites = append(b.ReturnSites, site)
+		}
+
+		if !Valid(instruction.OpCode) {
+			b.Invalid = true
+			break
+		}
+
+		targets, fallsThrough, unknown := flow(instruction)
+
+		b.Successors = append(b.Successors, targets...)
+		b.Unknown = unknown
+		b.Halts = instruction.OpCode == OpCodeHalt
+
+		if fallsThrough && isJump(instruction.OpCode) {
+			b.Successors = append(b.Successors, address)
+		}
+
+		if !fallsThrough || isJump(instruction.OpCode) {
+			break
+		}
+
+		if _, ok := leaders[address]; ok {
+			b.Successors = append(b.Successors, address)
+			break
+		}
+	}
+
+	b.End = address
+
+	return b
+}
+
+func (g *Graph) inRange(address int) bool {
+	return address >= 0 && address < len(g.Sequence)
+}
+
+// flow returns the immediate jump targets for an instruction, if execution can
+// continue with the next instruction and if the instruction jumps to an
+// unknown address. Conditional jumps with an immediate condition only follow
+// the branch that will be taken.
+func flow(i Instruction) ([]int, bool, bool) {
+	switch i.OpCode {
+	case OpCodeHalt:
+		return nil, false, false
+
+	case OpCodeJumpIfTrue, OpCodeJumpIfFalse:
+		var (
+			targets        []int
+			unknown        = i.Modes[1] != ParamModeImmediate
+			conditionKnown = i.Modes[0] == ParamModeImmediate
+			taken          = (i.OpCode == OpCodeJumpIfTrue) == (i.Params[0] != 0)
+		)
+
+		if conditionKnown && !taken {
+			return nil, true, false
+		}
+
+		if !unknown {
+			targets = []int{i.Params[1]}
+		}
+
+		return targets, !conditionKnown, unknown
+	}
+
+	return nil, true, false
+}
+
+// returnSite returns the pushed return address if the instruction stores a
+// constant at the current base, e.g. `21101,0,37,0`. Arguments are passed at
+// the following offsets and are not return sites.
+func returnSite(i Instruction) (int, bool) {
+	if i.OpCode != OpCodeAdd && i.OpCode != OpCodeMultiply {
+		return 0, false
+	}
+
+	if i.Modes[0] != ParamModeImmediate || i.Modes[1] != ParamModeImmediate || i.Modes[2] != ParamModeRelative || i.Params[2] != 0 {
+		return 0, false
+	}
+
+	if i.OpCode == OpCodeAdd {
+		return i.Params[0] + i.Params[1], true
+	}
+
+	return i.Params[0] * i.Params[1], true
+}
+
+func isJump(opCode int) bool {
+	return opCode == OpCodeJumpIfTrue || opCode == OpCodeJumpIfFalse
+}
+
+// WriteDOT writes the graph in Graphviz DOT format.
+func (g *Graph) WriteDOT(w io.Writer) {
+	fmt.Fprintln(w, "digraph intcode {")
+	fmt.Fprintln(w, "\tnode [shape=box, fontname=\"monospace\"];")
+
+	unknown := false
+
+	for _, start := range g.blockStarts() {
+		b := g.Blocks[start]
+		lines := make([]string, len(b.Instructions))
+
+		for i, instruction := range b.Instructions {
+			lines[i] = fmt.Sprintf("%04d  %s\\l", instruction.Address, instruction)
+		}
+
+		fmt.Fprintf(w, "\tb%d [label=\"%s\"];\n", b.Start, strings.Join(lines, ""))
+
+		for _, s := range b.Successors {
+			if _, ok := g.Blocks[s]; ok {
+				fmt.Fprintf(w, "\tb%d -> b%d;\n", b.Start, s)
+			}
+		}
+
+		for _, s := range b.ReturnSites {
+			fmt.Fprintf(w, "\tb%d -> b%d [style=dotted, label=\"return\"];\n", b.Start, s)
+		}
+
+		if b.Unknown {
+			unknown = true
+
+			fmt.Fprintf(w, "\tb%d -> unknown [style=dashed];\n", b.Start)
+		}
+	}
+
+	if unknown {
+		fmt.Fprintln(w, "\tunknown [shape=ellipse, label=\"?\"];")
+	}
+
+	fmt.Fprintln(w, "}")
+}
+
+// WriteDisassembly writes every address in the program, annotated as either
+// code or data. Instructions are written on a single line and every basic
+// block is preceded by a label.
+func (g *Graph) WriteDisassembly(w io.Writer) {
+	for address := 0; address < len(g.Sequence); {
+		if _, ok := g.Blocks[address]; ok {
+			fmt.Fprintf(w, "b%d:\n", address)
+		}
+
+		if _, ok := g.Code[address]; !ok {
+			fmt.Fprintf(w, "%04d  data  %d\n", address, g.Sequence[address])
+			address++
+
+			continue
+		}
+
+		_, start := g.starts[address]
+
+		instruction := Decode(g.Sequence, address)
+		if !start || !Valid(instruction.OpCode) {
+			fmt.Fprintf(w, "%04d  code  %d\n", address, g.Sequence[address])
+			address++
+
+			continue
+		}
+
+		fmt.Fprintf(w, "%04d  code  %s\n", address, instruction)
+		address += instruction.Length()
+	}
+}
+
+func (g *Graph) blockStarts() []int {
+	starts := make([]int, 0, len(g.Blocks))
+
+	for start := range g.Blocks {
+		starts = append(starts, start)
+	}
+
+	sort.Ints(starts)
+
+	return starts
+}
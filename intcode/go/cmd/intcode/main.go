@@ -0,0 +1,254 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"advent.of.code/intcode"
+)
+
+func main() {
+	var (
+		input = flag.String("input", "", "comma separated values to use as input")
+		patch = flag.String("patch", "", "comma separated address=value pairs to set before running")
+		trace = flag.Bool("trace", false, "write every executed instruction to stderr")
+		smc   = flag.Bool("smc", false, "mark executed addresses and report writes into them")
+		cfg   = flag.Bool("cfg", false, "write the control flow graph in DOT format instead of running")
+		dis   = flag.Bool("disassemble", false, "write the disassembly with code and data annotated instead of running")
+
+		solve        = flag.String("solve", "", "find symbol values making the program produce this target instead of running")
+		symbols      = flag.String("symbols", "", "comma separated address=name pairs to treat as symbols when solving")
+		inputSymbols = flag.String("input-symbols", "", "comma separated names of symbols read as input when solving")
+		ranges       = flag.String("ranges", "", "comma separated name=min..max ranges for each symbol when solving")
+		result       = flag.Int("result", 0, "address holding the result to solve for when halted")
+		resultOutput = flag.Bool("result-output", false, "use -result as the index of the output to solve for")
+	)
+
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		log.Fatal("missing file as input")
+	}
+
+	line, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("could not read file: %s", err.Error())
+	}
+
+	sequence, err := intcode.Parse(line)
+	if err != nil {
+		log.Fatalf("could not parse program: %s", err.Error())
+	}
+
+	if *cfg {
+		intcode.ControlFlow(sequence).WriteDOT(os.Stdout)
+		return
+	}
+
+	if *dis {
+		intcode.ControlFlow(sequence).WriteDisassembly(os.Stdout)
+		return
+	}
+
+	if *solve != "" {
+		target, err := strconv.Atoi(*solve)
+		if err != nil {
+			log.Fatalf("invalid target: %s", err.Error())
+		}
+
+		p := intcode.Problem{
+			Sequence: sequence,
+			Result:   *result,
+			Output:   *resultOutput,
+			Target:   target,
+		}
+
+		if p.Cells, err = parseSymbols(*symbols); err != nil {
+			log.Fatalf("invalid symbols: %s", err.Error())
+		}
+
+		if *inputSymbols != "" {
+			p.Inputs = strings.Split(*inputSymbols, ",")
+		}
+
+		if p.Ranges, err = parseRanges(*ranges); err != nil {
+			log.Fatalf("invalid ranges: %s", err.Error())
+		}
+
+		// Patches are applied to the program before solving.
+		c := intcode.New(sequence)
+		if err := applyPatch(c, *patch); err != nil {
+			log.Fatalf("invalid patch: %s", err.Error())
+		}
+
+		p.Sequence = c.Sequence
+
+		showSolutions(p)
+
+		return
+	}
+
+	c := intcode.New(sequence)
+	c.TrackExecuted = *smc
+
+	if *trace {
+		c.Trace = os.Stderr
+	}
+
+	if c.Input, err = parseValues(*input); err != nil {
+		log.Fatalf("invalid input: %s", err.Error())
+	}
+
+	if err := applyPatch(c, *patch); err != nil {
+		log.Fatalf("invalid patch: %s", err.Error())
+	}
+
+	if err := c.Process(); err != nil {
+		log.Fatalf("program failed: %s", err.Error())
+	}
+
+	if c.Waiting {
+		fmt.Println("program is waiting for more input at position", c.Pointer)
+	}
+
+	fmt.Println("output:", strings.Trim(strings.ReplaceAll(fmt.Sprint(c.Output), " ", ","), "[]"))
+	fmt.Println("position 0:", c.Sequence[0])
+
+	if *smc {
+		fmt.Println("self-modifications:", len(c.SelfModifications))
+
+		for _, m := range c.SelfModifications {
+			fmt.Printf("  %04d wrote %d to %d (was %d)\n", m.Pointer, m.New, m.Address, m.Old)
+		}
+	}
+}
+
+func showSolutions(p intcode.Problem) {
+	solutions, expr, err := intcode.Solve(p)
+	if err != nil {
+		log.Fatalf("could not solve: %s", err.Error())
+	}
+
+	if expr != nil {
+		fmt.Println("solved symbolically:", expr)
+	} else {
+		fmt.Println("solved by brute force")
+	}
+
+	fmt.Println("solutions:", len(solutions))
+
+	for _, s := range solutions {
+		names := make([]string, 0, len(s))
+		for name := range s {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		values := make([]string, len(names))
+		for i, name := range names {
+			values[i] = fmt.Sprintf("%s=%d", name, s[name])
+		}
+
+		fmt.Println(" ", strings.Join(values, " "))
+	}
+}
+
+func parseSymbols(s string) (map[int]string, error) {
+	symbols := map[int]string{}
+
+	if s == "" {
+		return symbols, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.Split(pair, "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected address=name, got '%s'", pair)
+		}
+
+		address, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid address '%s'", parts[0])
+		}
+
+		symbols[address] = strings.TrimSpace(parts[1])
+	}
+
+	return symbols, nil
+}
+
+func parseRanges(s string) (map[string]intcode.Range, error) {
+	ranges := map[string]intcode.Range{}
+
+	if s == "" {
+		return ranges, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.Split(pair, "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected name=min..max, got '%s'", pair)
+		}
+
+		bounds := strings.Split(parts[1], "..")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("expected min..max, got '%s'", parts[1])
+		}
+
+		low, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid min '%s'", bounds[0])
+		}
+
+		high, err := strconv.Atoi(strings.TrimSpace(bounds[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid max '%s'", bounds[1])
+		}
+
+		ranges[strings.TrimSpace(parts[0])] = intcode.Range{Min: low, Max: high}
+	}
+
+	return ranges, nil
+}
+
+func parseValues(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	return intcode.Parse([]byte(s))
+}
+
+func applyPatch(c *intcode.Computer, s string) error {
+	if s == "" {
+		return nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.Split(pair, "=")
+		if len(parts) != 2 {
+			return fmt.Errorf("expected address=value, got '%s'", pair)
+		}
+
+		address, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+		if err != nil || address < 0 || address >= len(c.Sequence) {
+			return fmt.Errorf("invalid address '%s'", parts[0])
+		}
+
+		value, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return fmt.Errorf("invalid value '%s'", parts[1])
+		}
+
+		c.Sequence[address] = value
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package intcode
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SelfModification represents a write into an address that has already been
+// executed as part of an instruction.
+type SelfModification struct {
+	Pointer int
+	Address int
+	Old     int
+	New     int
+}
+
+// InstructionError is returned when the computer finds an unknown op code or
+// parameter mode.
+type InstructionError struct {
+	Pointer int
+	Value   int
+}
+
+func (e *InstructionError) Error() string {
+	return fmt.Sprintf("unknown instruction at position %d: %d", e.Pointer, e.Value)
+}
+
+// AddressError is returned when the instruction at Pointer uses an address
+// that's negative or, if the memory is fixed, outside of the program.
+type AddressError struct {
+	Pointer int
+	Address int
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("address %d out of bounds at position %d", e.Address, e.Pointer)
+}
+
+// Computer represents an Intcode computer. Input is consumed from the front
+// and the computer stops and sets Waiting if it needs input that hasn't been
+// provided yet.
+type Computer struct {
+	Input         []int
+	Output        []int
+	Pointer       int
+	Base          int
+	Sequence      []int
+	Halted        bool
+	Waiting       bool
+	PauseAtOutput bool
+
+	// FixedMemory makes addresses outside of the program an error instead of
+	// growing the memory, as the first computers were specified.
+	FixedMemory bool
+
+	// OpCodes restricts the computer to the given op codes if set and
+	// PositionModeOnly to parameters in position mode, so a program for the
+	// first computers fails on anything they didn't support.
+	OpCodes          []int
+	PositionModeOnly bool
+
+	// TrackExecuted marks every address executed (op code and parameters) in
+	// Executed and records writes changing those addresses in
+	// SelfModifications. An instruction writing into itself only counts once
+	// it has been executed before.
+	TrackExecuted     bool
+	Executed          map[int]struct{}
+	SelfModifications []SelfModification
+
+	// Trace will get every executed instruction written to it if set.
+	Trace io.Writer
+}
+
+// Parse parses a comma separated Intcode program.
+func Parse(data []byte) ([]int, error) {
+	var (
+		stringSequence = strings.Split(strings.TrimSpace(string(data)), ",")
+		sequence       = make([]int, len(stringSequence))
+	)
+
+	for i := range sequence {
+		v, err := strconv.Atoi(strings.TrimSpace(stringSequence[i]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value at position %d: %w", i, err)
+		}
+
+		sequence[i] = v
+	}
+
+	return sequence, nil
+}
+
+// New creates a new computer with a copy of the sequence.
+func New(sequence []int) *Computer {
+	c := Computer{
+		Sequence: make([]int, len(sequence)),
+		Executed: map[int]struct{}{},
+	}
+
+	copy(c.Sequence, sequence)
+
+	return &c
+}
+
+// Process runs the program until it halts, needs input that hasn't been
+// provided or, if PauseAtOutput is set, has produced an output.
+func (c *Computer) Process() error {
+	for {
+		ok, err := c.Step()
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return nil
+		}
+	}
+}
+
+// Step executes a single instruction and returns true if the computer can
+// continue to the next one.
+func (c *Computer) Step() (bool, error) {
+	if c.Halted {
+		return false, nil
+	}
+
+	if err := c.check(c.Pointer); err != nil {
+		return false, err
+	}
+
+	instruction := Decode(c.Sequence, c.Pointer)
+
+	if !Valid(instruction.OpCode) || !c.supported(instruction) {
+		return false, &InstructionError{Pointer: c.Pointer, Value: instruction.Value}
+	}
+
+	if instruction.OpCode == OpCodeStore && len(c.Input) == 0 {
+		c.Waiting = true
+		return false, nil
+	}
+
+	c.Waiting = false
+
+	// Resolve the address for every parameter before executing anything so
+	// a failing instruction never leaves the memory half updated.
+	addresses := make([]int, instruction.Length()-1)
+
+	for i := range addresses {
+		address, err := c.address(i + 1)
+		if err != nil {
+			return false, err
+		}
+
+		addresses[i] = address
+	}
+
+	if c.Trace != nil {
+		fmt.Fprintf(c.Trace, "%04d  %s\n", c.Pointer, instruction)
+	}
+
+	// The instruction is marked after it's executed so only writes into
+	// instructions executed before count as self-modifications.
+	if c.TrackExecuted {
+		defer c.markExecuted(c.Pointer, instruction.Length())
+	}
+
+	sequenceFor := func(pos int) int {
+		return c.Sequence[addresses[pos-1]]
+	}
+
+	switch instruction.OpCode {
+	case OpCodeHalt:
+		c.Halted = true
+		return false, nil
+
+	case OpCodeAdd:
+		c.write(addresses[2], sequenceFor(1)+sequenceFor(2))
+
+	case OpCodeMultiply:
+		c.write(addresses[2], sequenceFor(1)*sequenceFor(2))
+
+	case OpCodeStore:
+		c.write(addresses[0], c.Input[0])
+		c.Input = c.Input[1:]
+
+	case OpCodeOutput:
+		c.Output = append(c.Output, sequenceFor(1))
+
+		if c.PauseAtOutput {
+			c.Pointer += instruction.Length()
+			return false, nil
+		}
+
+	case OpCodeJumpIfTrue:
+		if sequenceFor(1) != 0 {
+			c.Pointer = sequenceFor(2)
+			return true, nil
+		}
+
+	case OpCodeJumpIfFalse:
+		if sequenceFor(1) == 0 {
+			c.Pointer = sequenceFor(2)
+			return true, nil
+		}
+
+	case OpCodeLessThan:
+		if sequenceFor(1) < sequenceFor(2) {
+			c.write(addresses[2], 1)
+		} else {
+			c.write(addresses[2], 0)
+		}
+
+	case OpCodeEquals:
+		if sequenceFor(1) == sequenceFor(2) {
+			c.write(addresses[2], 1)
+		} else {
+			c.write(addresses[2], 0)
+		}
+
+	case OpCodeAdjustBase:
+		c.Base += sequenceFor(1)
+	}
+
+	c.Pointer += instruction.Length()
+
+	return true, nil
+}
+
+// supported returns true if the instruction is allowed by OpCodes and
+// PositionModeOnly.
+func (c *Computer) supported(instruction Instruction) bool {
+	if c.PositionModeOnly && instruction.Value/100 != 0 {
+		return false
+	}
+
+	if c.OpCodes == nil {
+		return true
+	}
+
+	for _, opCode := range c.OpCodes {
+		if opCode == instruction.OpCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// address returns the address to read or write for the parameter at the given
+// position, growing the memory if it's outside of the current sequence.
+func (c *Computer) address(argumentPosition int) (int, error) {
+	var (
+		pointer       = 0
+		modePositions = c.Sequence[c.Pointer] / 100
+	)
+
+	for i := 1; i < argumentPosition; i++ {
+		modePositions /= 10
+	}
+
+	if err := c.check(c.Pointer + argumentPosition); err != nil {
+		return 0, err
+	}
+
+	switch modePositions % 10 {
+	case ParamModePosition:
+		pointer = c.Sequence[c.Pointer+argumentPosition]
+	case ParamModeImmediate:
+		pointer = c.Pointer + argumentPosition
+	case ParamModeRelative:
+		pointer = c.Sequence[c.Pointer+argumentPosition] + c.Base
+	default:
+		return 0, &InstructionError{Pointer: c.Pointer, Value: c.Sequence[c.Pointer]}
+	}
+
+	if err := c.check(pointer); err != nil {
+		return 0, err
+	}
+
+	return pointer, nil
+}
+
+// check returns an error if the address can't be used, growing the memory if
+// it's outside of the current sequence and the memory isn't fixed.
+func (c *Computer) check(address int) error {
+	if address < 0 || (c.FixedMemory && address >= len(c.Sequence)) {
+		return &AddressError{Pointer: c.Pointer, Address: address}
+	}
+
+	if address >= len(c.Sequence) {
+		c.Sequence = append(c.Sequence, make([]int, address-len(c.Sequence)+1)...)
+	}
+
+	return nil
+}
+
+func (c *Computer) write(address, value int) {
+	if c.TrackExecuted && c.Sequence[address] != value {
+		if _, ok := c.Executed[address]; ok {
+			m := SelfModification{
+				Pointer: c.Pointer,
+				Address: address,
+				Old:     c.Sequence[address],
+				New:     value,
+			}
+
+			c.SelfModifications = append(c.SelfModifications, m)
+
+			if c.Trace != nil {
+				fmt.Fprintf(c.Trace, "%04d  self-modification: [%d] %d -> %d\n", m.Pointer, m.Address, m.Old, m.New)
+			}
+		}
+	}
+
+	c.Sequence[address] = value
+}
+
+func (c *Computer) markExecuted(address, length int) {
+	if c.Executed == nil {
+		c.Executed = map[int]struct{}{}
+	}
+
+	for i := address; i < address+length; i++ {
+		c.Executed[i] = struct{}{}
+	}
+}
@@ -0,0 +1,63 @@
+package intcode
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelfModifications(t *testing.T) {
+	cases := []struct {
+		name     string
+		sequence []int
+		want     []SelfModification
+	}{
+		{
+			name:     "write into own parameter",
+			sequence: []int{1, 0, 0, 3, 99},
+			want:     []SelfModification{},
+		},
+		{
+			name:     "write without change",
+			sequence: []int{1101, 0, 0, 9, 1101, 0, 0, 1, 99, 0},
+			want:     []SelfModification{},
+		},
+		{
+			name:     "write into earlier instructions",
+			sequence: []int{1, 0, 0, 3, 1101, 5, 5, 0, 1, 4, 4, 4, 99},
+			want: []SelfModification{
+				{Pointer: 4, Address: 0, Old: 1, New: 10},
+				{Pointer: 8, Address: 4, Old: 1101, New: 2202},
+			},
+		},
+		{
+			name: "loop writing into itself",
+			// Counts down the first parameter of the add at address 0 by
+			// writing into it, which only counts once it runs again.
+			sequence: []int{1101, 5, -1, 1, 1005, 1, 0, 99},
+			want: []SelfModification{
+				{Pointer: 0, Address: 1, Old: 4, New: 3},
+				{Pointer: 0, Address: 1, Old: 3, New: 2},
+				{Pointer: 0, Address: 1, Old: 2, New: 1},
+				{Pointer: 0, Address: 1, Old: 1, New: 0},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		c := New(tc.sequence)
+		c.TrackExecuted = true
+
+		if err := c.Process(); err != nil {
+			t.Fatalf("%s: unexpected error: %s", tc.name, err)
+		}
+
+		got := c.SelfModifications
+		if got == nil {
+			got = []SelfModification{}
+		}
+
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module advent.of.code/intcode
+
+go 1.13
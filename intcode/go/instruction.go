@@ -0,0 +1,145 @@
+package intcode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// * ParamModePosition  -> Use the value found at slice[n]
+// * ParamModeImmediate -> Use the value n
+// * ParamModeRelative  -> Use the value found at slice[BASE+n]
+const (
+	ParamModePosition = iota
+	ParamModeImmediate
+	ParamModeRelative
+)
+
+// Op codes supported by the computer.
+const (
+	OpCodeAdd         = 1
+	OpCodeMultiply    = 2
+	OpCodeStore       = 3
+	OpCodeOutput      = 4
+	OpCodeJumpIfTrue  = 5
+	OpCodeJumpIfFalse = 6
+	OpCodeLessThan    = 7
+	OpCodeEquals      = 8
+	OpCodeAdjustBase  = 9
+	OpCodeHalt        = 99
+)
+
+// nolint: gochecknoglobals
+var jumpMap = map[int]int{
+	OpCodeAdd:         4,
+	OpCodeMultiply:    4,
+	OpCodeStore:       2,
+	OpCodeOutput:      2,
+	OpCodeJumpIfTrue:  3,
+	OpCodeJumpIfFalse: 3,
+	OpCodeLessThan:    4,
+	OpCodeEquals:      4,
+	OpCodeAdjustBase:  2,
+	OpCodeHalt:        1,
+}
+
+// nolint: gochecknoglobals
+var mnemonicMap = map[int]string{
+	OpCodeAdd:         "add",
+	OpCodeMultiply:    "mul",
+	OpCodeStore:       "in",
+	OpCodeOutput:      "out",
+	OpCodeJumpIfTrue:  "jnz",
+	OpCodeJumpIfFalse: "jz",
+	OpCodeLessThan:    "lt",
+	OpCodeEquals:      "eq",
+	OpCodeAdjustBase:  "arb",
+	OpCodeHalt:        "hlt",
+}
+
+// Instruction represents a decoded instruction with its parameters.
+type Instruction struct {
+	Address int
+	Value   int
+	OpCode  int
+	Modes   []int
+	Params  []int
+}
+
+// Decode decodes the instruction found at address. Parameters past the end of
+// the sequence are read as zero, the same way the computer sees them.
+func Decode(sequence []int, address int) Instruction {
+	var (
+		value  = sequence[address]
+		opCode = value % 100
+		length = Length(opCode)
+		i      = Instruction{Address: address, Value: value, OpCode: opCode}
+	)
+
+	if !Valid(opCode) {
+		return i
+	}
+
+	modePositions := value / 100
+
+	for n := 1; n < length; n++ {
+		param := 0
+		if address+n < len(sequence) {
+			param = sequence[address+n]
+		}
+
+		i.Modes = append(i.Modes, modePositions%10)
+		i.Params = append(i.Params, param)
+		modePositions /= 10
+	}
+
+	return i
+}
+
+// Valid returns true if the op code is known by the computer.
+func Valid(opCode int) bool {
+	_, ok := jumpMap[opCode]
+	return ok
+}
+
+// Length returns the number of words used by an instruction with the given op
+// code, including the op code itself. Unknown op codes occupy one word.
+func Length(opCode int) int {
+	if n, ok := jumpMap[opCode]; ok {
+		return n
+	}
+
+	return 1
+}
+
+// Length returns the number of words the instruction occupies.
+func (i Instruction) Length() int {
+	return Length(i.OpCode)
+}
+
+func (i Instruction) String() string {
+	mnemonic, ok := mnemonicMap[i.OpCode]
+	if !ok {
+		return fmt.Sprintf("data %d", i.Value)
+	}
+
+	params := make([]string, len(i.Params))
+
+	for n, p := range i.Params {
+		switch i.Modes[n] {
+		case ParamModePosition:
+			params[n] = fmt.Sprintf("[%d]", p)
+		case ParamModeImmediate:
+			params[n] = fmt.Sprintf("%d", p)
+		case ParamModeRelative:
+			params[n] = fmt.Sprintf("[base%+d]", p)
+		default:
+			params[n] = fmt.Sprintf("?%d", p)
+		}
+	}
+
+	if len(params) == 0 {
+		return mnemonic
+	}
+
+	return fmt.Sprintf("%-3s %s", mnemonic, strings.Join(params, ", "))
+}
@@ -0,0 +1,640 @@
+package intcode
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// unknownSymbol is used in expressions that can't be known statically, e.g.
+// values read from an address that depends on a symbol.
+const unknownSymbol = "?"
+
+// maxSteps is the number of instructions to execute, symbolically or on a
+// computer, before giving up, to not loop forever on programs never halting.
+const maxSteps = 1000000
+
+// ErrUnsupported is returned when a program can't be executed symbolically.
+var ErrUnsupported = errors.New("unsupported symbolic operation")
+
+// Expr represents a polynomial with integer coefficients. Each key is a
+// monomial, the names of the symbols multiplied sorted and joined by `*`. The
+// constant term has an empty key.
+type Expr map[string]int
+
+// Constant creates an expression for a concrete value.
+func Constant(v int) Expr {
+	return Expr{"": v}
+}
+
+// Symbol creates an expression for a single symbol.
+func Symbol(name string) Expr {
+	return Expr{name: 1}
+}
+
+// Add returns the sum of two expressions.
+func (e Expr) Add(o Expr) Expr {
+	result := Expr{}
+
+	for k, v := range e {
+		result[k] += v
+	}
+
+	for k, v := range o {
+		result[k] += v
+	}
+
+	return result.normalize()
+}
+
+// Multiply returns the product of two expressions.
+func (e Expr) Multiply(o Expr) Expr {
+	if e.Unknown() || o.Unknown() {
+		return Symbol(unknownSymbol)
+	}
+
+	result := Expr{}
+
+	for k1, v1 := range e {
+		for k2, v2 := range o {
+			result[monomial(k1, k2)] += v1 * v2
+		}
+	}
+
+	return result.normalize()
+}
+
+// Value returns the concrete value and true if the expression has no symbols.
+func (e Expr) Value() (int, bool) {
+	for k := range e {
+		if k != "" {
+			return 0, false
+		}
+	}
+
+	return e[""], true
+}
+
+// Unknown returns true if the expression depends on a value that can't be
+// known statically.
+func (e Expr) Unknown() bool {
+	_, ok := e[unknownSymbol]
+	return ok
+}
+
+// Linear returns true if no monomial has more than one symbol.
+func (e Expr) Linear() bool {
+	for k := range e {
+		if strings.Contains(k, "*") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Symbols returns the sorted names of all symbols in the expression.
+func (e Expr) Symbols() []string {
+	seen := map[string]struct{}{}
+
+	for k := range e {
+		if k == "" {
+			continue
+		}
+
+		for _, s := range strings.Split(k, "*") {
+			seen[s] = struct{}{}
+		}
+	}
+
+	symbols := []string{}
+	for s := range seen {
+		symbols = append(symbols, s)
+	}
+
+	sort.Strings(symbols)
+
+	return symbols
+}
+
+// Evaluate evaluates the expression with the given values for each symbol.
+func (e Expr) Evaluate(values map[string]int) int {
+	total := 0
+
+	for k, v := range e {
+		if k != "" {
+			for _, s := range strings.Split(k, "*") {
+				v *= values[s]
+			}
+		}
+
+		total += v
+	}
+
+	return total
+}
+
+func (e Expr) String() string {
+	keys := []string{}
+
+	for k := range e {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	terms := []string{}
+
+	for _, k := range keys {
+		switch e[k] {
+		case 1:
+			terms = append(terms, k)
+		default:
+			terms = append(terms, fmt.Sprintf("%d*%s", e[k], k))
+		}
+	}
+
+	if c := e[""]; c != 0 || len(terms) == 0 {
+		terms = append(terms, fmt.Sprintf("%d", c))
+	}
+
+	return strings.ReplaceAll(strings.Join(terms, " + "), "+ -", "- ")
+}
+
+func (e Expr) normalize() Expr {
+	if e.Unknown() {
+		return Symbol(unknownSymbol)
+	}
+
+	for k, v := range e {
+		if v == 0 && k != "" {
+			delete(e, k)
+		}
+	}
+
+	return e
+}
+
+func monomial(a, b string) string {
+	symbols := []string{}
+
+	for _, k := range []string{a, b} {
+		if k != "" {
+			symbols = append(symbols, strings.Split(k, "*")...)
+		}
+	}
+
+	sort.Strings(symbols)
+
+	return strings.Join(symbols, "*")
+}
+
+// Range represents an inclusive range of values for a symbol.
+type Range struct {
+	Min int
+	Max int
+}
+
+// Problem describes a program with some memory cells and inputs replaced with
+// symbols and the value we want it to produce.
+type Problem struct {
+	Sequence []int
+	Cells    map[int]string
+	Inputs   []string
+	Ranges   map[string]Range
+
+	// Result is the memory address holding the result when the program has
+	// halted. If Output is set it's instead the index of the output to use.
+	Result int
+	Output bool
+	Target int
+}
+
+// Solution holds the value for each symbol.
+type Solution map[string]int
+
+// Solve finds all values for the symbols within their ranges that makes the
+// program produce the target. The program is executed symbolically to build
+// an expression for the result which is solved directly if it's linear. If the
+// program can't be executed symbolically, e.g. because a symbol is used as a
+// jump condition, every combination is run on a computer instead. The
+// expression is returned if one was found.
+func Solve(p Problem) ([]Solution, Expr, error) {
+	for _, s := range p.symbols() {
+		if _, ok := p.Ranges[s]; !ok {
+			return nil, nil, fmt.Errorf("missing range for symbol '%s'", s)
+		}
+	}
+
+	expr, err := p.Symbolic()
+	if err != nil {
+		if errors.Is(err, ErrUnsupported) {
+			return p.bruteForce(), nil, nil
+		}
+
+		return nil, nil, err
+	}
+
+	// The result depends on a value read through a symbolic address.
+	if expr.Unknown() {
+		return p.bruteForce(), nil, nil
+	}
+
+	if expr.Linear() {
+		return p.solveLinear(expr), expr, nil
+	}
+
+	solutions := []Solution{}
+
+	p.each(p.symbols(), Solution{}, func(s Solution) {
+		if expr.Evaluate(s) == p.Target {
+			solutions = append(solutions, s)
+		}
+	})
+
+	return solutions, expr, nil
+}
+
+// Symbolic executes the program with expressions instead of values and
+// returns the expression for the result.
+func (p Problem) Symbolic() (Expr, error) {
+	var (
+		memory  = make([]Expr, len(p.Sequence))
+		inputs  = p.Inputs
+		outputs = []Expr{}
+		pointer = 0
+		base    = 0
+	)
+
+	for i, v := range p.Sequence {
+		memory[i] = Constant(v)
+	}
+
+	for address, name := range p.Cells {
+		if address < 0 || address >= len(memory) {
+			return nil, fmt.Errorf("symbol '%s' at address %d is outside of the program", name, address)
+		}
+
+		memory[address] = Symbol(name)
+	}
+
+	concrete := func(address int) (int, error) {
+		if address < 0 {
+			return 0, fmt.Errorf("%w: negative address %d", ErrUnsupported, address)
+		}
+
+		for address >= len(memory) {
+			memory = append(memory, Constant(0))
+		}
+
+		v, ok := memory[address].Value()
+		if !ok {
+			return 0, fmt.Errorf("%w: symbolic value used at %d", ErrUnsupported, address)
+		}
+
+		return v, nil
+	}
+
+	// address returns the address for the given parameter. A symbolic address
+	// is returned as nil since it can't be known.
+	address := func(argumentPosition, mode int) (*int, error) {
+		param := memory[pointer+argumentPosition]
+
+		if mode == ParamModeImmediate {
+			a := pointer + argumentPosition
+			return &a, nil
+		}
+
+		v, ok := param.Value()
+		if !ok {
+			return nil, nil
+		}
+
+		if mode == ParamModeRelative {
+			v += base
+		}
+
+		if v < 0 {
+			return nil, fmt.Errorf("%w: negative address %d", ErrUnsupported, v)
+		}
+
+		for v >= len(memory) {
+			memory = append(memory, Constant(0))
+		}
+
+		return &v, nil
+	}
+
+	for steps := 0; ; steps++ {
+		if steps > maxSteps {
+			return nil, fmt.Errorf("%w: no halt after %d steps", ErrUnsupported, maxSteps)
+		}
+
+		value, err := concrete(pointer)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			opCode = value % 100
+			modes  = []int{value / 100 % 10, value / 1000 % 10, value / 10000 % 10}
+			params = make([]*int, Length(opCode)-1)
+		)
+
+		if !Valid(opCode) {
+			return nil, fmt.Errorf("%w: unknown instruction at position %d, opCode: %d", ErrUnsupported, pointer, opCode)
+		}
+
+		for i := range params {
+			for pointer+i+1 >= len(memory) {
+				memory = append(memory, Constant(0))
+			}
+
+			if params[i], err = address(i+1, modes[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		read := func(i int) Expr {
+			if params[i] == nil {
+				return Symbol(unknownSymbol)
+			}
+
+			return memory[*params[i]]
+		}
+
+		write := func(i int, e Expr) error {
+			if params[i] == nil {
+				return fmt.Errorf("%w: write to symbolic address at %d", ErrUnsupported, pointer)
+			}
+
+			memory[*params[i]] = e
+
+			return nil
+		}
+
+		condition := func(i int) (int, error) {
+			v, ok := read(i).Value()
+			if !ok {
+				return 0, fmt.Errorf("%w: symbolic condition at %d", ErrUnsupported, pointer)
+			}
+
+			return v, nil
+		}
+
+		next := pointer + Length(opCode)
+
+		switch opCode {
+		case OpCodeHalt:
+			if p.Output {
+				if p.Result < 0 || p.Result >= len(outputs) {
+					return nil, fmt.Errorf("program only produced %d outputs", len(outputs))
+				}
+
+				return outputs[p.Result], nil
+			}
+
+			if p.Result < 0 || p.Result >= len(memory) {
+				return nil, fmt.Errorf("result address %d is outside of memory", p.Result)
+			}
+
+			return memory[p.Result], nil
+
+		case OpCodeAdd:
+			err = write(2, read(0).Add(read(1)))
+
+		case OpCodeMultiply:
+			err = write(2, read(0).Multiply(read(1)))
+
+		case OpCodeStore:
+			if len(inputs) == 0 {
+				return nil, fmt.Errorf("%w: not enough input symbols", ErrUnsupported)
+			}
+
+			err = write(0, Symbol(inputs[0]))
+			inputs = inputs[1:]
+
+		case OpCodeOutput:
+			outputs = append(outputs, read(0))
+
+		case OpCodeJumpIfTrue, OpCodeJumpIfFalse:
+			var c, target int
+
+			if c, err = condition(0); err != nil {
+				return nil, err
+			}
+
+			if (opCode == OpCodeJumpIfTrue) == (c != 0) {
+				if target, err = condition(1); err != nil {
+					return nil, err
+				}
+
+				next = target
+			}
+
+		case OpCodeLessThan, OpCodeEquals:
+			var a, b int
+
+			if a, err = condition(0); err != nil {
+				return nil, err
+			}
+
+			if b, err = condition(1); err != nil {
+				return nil, err
+			}
+
+			result := 0
+			if (opCode == OpCodeLessThan && a < b) || (opCode == OpCodeEquals && a == b) {
+				result = 1
+			}
+
+			err = write(2, Constant(result))
+
+		case OpCodeAdjustBase:
+			var v int
+
+			if v, err = condition(0); err != nil {
+				return nil, err
+			}
+
+			base += v
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		pointer = next
+	}
+}
+
+// solveLinear solves a linear expression by trying every value for all but
+// one symbol and calculating the last one.
+func (p Problem) solveLinear(expr Expr) []Solution {
+	var (
+		solutions = []Solution{}
+		symbols   = expr.Symbols()
+		free      = []string{}
+	)
+
+	// Symbols not part of the expression can be anything.
+	for _, s := range p.symbols() {
+		if _, ok := expr[s]; !ok {
+			free = append(free, s)
+		}
+	}
+
+	if len(symbols) == 0 {
+		if expr[""] == p.Target {
+			p.each(free, Solution{}, func(s Solution) {
+				solutions = append(solutions, s)
+			})
+		}
+
+		return solutions
+	}
+
+	var (
+		last        = symbols[len(symbols)-1]
+		coefficient = expr[last]
+		r           = p.Ranges[last]
+	)
+
+	p.each(append(symbols[:len(symbols)-1:len(symbols)-1], free...), Solution{}, func(s Solution) {
+		rest := p.Target - expr.Evaluate(s)
+
+		if rest%coefficient != 0 {
+			return
+		}
+
+		v := rest / coefficient
+		if v < r.Min || v > r.Max {
+			return
+		}
+
+		s[last] = v
+		solutions = append(solutions, s)
+	})
+
+	sortSolutions(solutions, p.symbols())
+
+	return solutions
+}
+
+// bruteForce runs the program on a computer for every combination of values.
+func (p Problem) bruteForce() []Solution {
+	solutions := []Solution{}
+
+	p.each(p.symbols(), Solution{}, func(s Solution) {
+		c := New(p.Sequence)
+
+		for address, name := range p.Cells {
+			c.Sequence[address] = s[name]
+		}
+
+		for _, name := range p.Inputs {
+			c.Input = append(c.Input, s[name])
+		}
+
+		if v, ok := run(c, p.Result, p.Output); ok && v == p.Target {
+			solutions = append(solutions, s)
+		}
+	})
+
+	return solutions
+}
+
+// run processes the computer until it halts and returns the result. Programs
+// failing or not halting within maxSteps for the given values have no result.
+func run(c *Computer, result int, output bool) (int, bool) {
+	for steps := 0; steps < maxSteps; steps++ {
+		ok, err := c.Step()
+		if err != nil {
+			return 0, false
+		}
+
+		if !ok {
+			break
+		}
+	}
+
+	if !c.Halted {
+		return 0, false
+	}
+
+	if output {
+		if result < 0 || result >= len(c.Output) {
+			return 0, false
+		}
+
+		return c.Output[result], true
+	}
+
+	if result < 0 || result >= len(c.Sequence) {
+		return 0, false
+	}
+
+	return c.Sequence[result], true
+}
+
+// each calls f with every combination of values for the symbols, added to the
+// given solution.
+func (p Problem) each(symbols []string, s Solution, f func(Solution)) {
+	if len(symbols) == 0 {
+		combination := Solution{}
+		for k, v := range s {
+			combination[k] = v
+		}
+
+		f(combination)
+
+		return
+	}
+
+	r := p.Ranges[symbols[0]]
+
+	for v := r.Min; v <= r.Max; v++ {
+		s[symbols[0]] = v
+		p.each(symbols[1:], s, f)
+	}
+
+	delete(s, symbols[0])
+}
+
+func (p Problem) symbols() []string {
+	var (
+		seen    = map[string]struct{}{}
+		symbols = []string{}
+	)
+
+	for _, name := range p.Cells {
+		seen[name] = struct{}{}
+	}
+
+	for _, name := range p.Inputs {
+		seen[name] = struct{}{}
+	}
+
+	for name := range seen {
+		symbols = append(symbols, name)
+	}
+
+	sort.Strings(symbols)
+
+	return symbols
+}
+
+func sortSolutions(solutions []Solution, symbols []string) {
+	sort.Slice(solutions, func(i, j int) bool {
+		for _, s := range symbols {
+			if solutions[i][s] != solutions[j][s] {
+				return solutions[i][s] < solutions[j][s]
+			}
+		}
+
+		return false
+	})
+}
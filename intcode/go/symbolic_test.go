@@ -0,0 +1,242 @@
+package intcode
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExpr(t *testing.T) {
+	var (
+		a = Symbol("a")
+		b = Symbol("b")
+	)
+
+	cases := []struct {
+		name    string
+		expr    Expr
+		str     string
+		linear  bool
+		symbols []string
+		value   int
+	}{
+		{
+			name:    "linear",
+			expr:    a.Multiply(Constant(3)).Add(b).Add(Constant(-2)),
+			str:     "3*a + b - 2",
+			linear:  true,
+			symbols: []string{"a", "b"},
+			value:   9,
+		},
+		{
+			name:    "product",
+			expr:    a.Multiply(b).Add(a.Multiply(Constant(-1))),
+			str:     "-1*a + a*b",
+			linear:  false,
+			symbols: []string{"a", "b"},
+			value:   8,
+		},
+		{
+			name:    "cancelled",
+			expr:    a.Add(a.Multiply(Constant(-1))).Add(Constant(4)),
+			str:     "4",
+			linear:  true,
+			symbols: []string{},
+			value:   4,
+		},
+	}
+
+	for _, tc := range cases {
+		if got := tc.expr.String(); got != tc.str {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.str)
+		}
+
+		if got := tc.expr.Linear(); got != tc.linear {
+			t.Errorf("%s: got linear %t, want %t", tc.name, got, tc.linear)
+		}
+
+		if got := tc.expr.Symbols(); !reflect.DeepEqual(got, tc.symbols) {
+			t.Errorf("%s: got symbols %v, want %v", tc.name, got, tc.symbols)
+		}
+
+		if got := tc.expr.Evaluate(map[string]int{"a": 2, "b": 5}); got != tc.value {
+			t.Errorf("%s: got %d for a=2 b=5, want %d", tc.name, got, tc.value)
+		}
+	}
+
+	if v, ok := Constant(3).Add(Constant(4)).Value(); !ok || v != 7 {
+		t.Errorf("got value %d (%t), want 7", v, ok)
+	}
+
+	if _, ok := a.Add(Constant(4)).Value(); ok {
+		t.Error("expected no value for an expression with symbols")
+	}
+
+	if !Symbol(unknownSymbol).Multiply(a).Unknown() || !a.Add(Symbol(unknownSymbol)).Unknown() {
+		t.Error("expected unknown values to stay unknown")
+	}
+}
+
+func TestSolve(t *testing.T) {
+	cases := []struct {
+		name    string
+		problem Problem
+		expr    string
+		want    []Solution
+	}{
+		{
+			// [0] = ([9] + [10]) * [11]
+			name: "linear",
+			problem: Problem{
+				Sequence: []int{1, 9, 10, 0, 2, 0, 11, 0, 99, 0, 0, 3},
+				Cells:    map[int]string{9: "a", 10: "b"},
+				Ranges:   map[string]Range{"a": {Min: 0, Max: 5}, "b": {Min: 0, Max: 5}},
+				Target:   12,
+			},
+			expr: "3*a + 3*b",
+			want: []Solution{
+				{"a": 0, "b": 4},
+				{"a": 1, "b": 3},
+				{"a": 2, "b": 2},
+				{"a": 3, "b": 1},
+				{"a": 4, "b": 0},
+			},
+		},
+		{
+			name: "linear without integer solution",
+			problem: Problem{
+				Sequence: []int{1, 9, 10, 0, 2, 0, 11, 0, 99, 0, 0, 3},
+				Cells:    map[int]string{9: "a", 10: "b"},
+				Ranges:   map[string]Range{"a": {Min: 0, Max: 5}, "b": {Min: 0, Max: 5}},
+				Target:   13,
+			},
+			expr: "3*a + 3*b",
+			want: []Solution{},
+		},
+		{
+			// [0] = [9] * [10]
+			name: "product",
+			problem: Problem{
+				Sequence: []int{2, 9, 10, 0, 99, 0, 0, 0, 0, 0, 0},
+				Cells:    map[int]string{9: "a", 10: "b"},
+				Ranges:   map[string]Range{"a": {Min: 1, Max: 6}, "b": {Min: 1, Max: 6}},
+				Target:   6,
+			},
+			expr: "a*b",
+			want: []Solution{
+				{"a": 1, "b": 6},
+				{"a": 2, "b": 3},
+				{"a": 3, "b": 2},
+				{"a": 6, "b": 1},
+			},
+		},
+		{
+			// Outputs five times the input.
+			name: "input and output",
+			problem: Problem{
+				Sequence: []int{3, 9, 1002, 9, 5, 9, 4, 9, 99, 0},
+				Inputs:   []string{"a"},
+				Ranges:   map[string]Range{"a": {Min: 0, Max: 10}},
+				Output:   true,
+				Target:   15,
+			},
+			expr: "5*a",
+			want: []Solution{{"a": 3}},
+		},
+		{
+			// Jumps past setting [0] to 2 unless [10] is zero, which
+			// can't be executed symbolically.
+			name: "symbol as jump condition",
+			problem: Problem{
+				Sequence: []int{1005, 10, 7, 1101, 1, 1, 0, 99, 0, 0, 0},
+				Cells:    map[int]string{10: "a"},
+				Ranges:   map[string]Range{"a": {Min: 0, Max: 3}},
+				Target:   2,
+			},
+			want: []Solution{{"a": 0}},
+		},
+	}
+
+	for _, tc := range cases {
+		solutions, expr, err := Solve(tc.problem)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+
+		if tc.expr == "" && expr != nil {
+			t.Errorf("%s: got expression %s, want none", tc.name, expr)
+		}
+
+		if tc.expr != "" && (expr == nil || expr.String() != tc.expr) {
+			t.Errorf("%s: got expression %v, want %s", tc.name, expr, tc.expr)
+		}
+
+		if !reflect.DeepEqual(solutions, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, solutions, tc.want)
+		}
+	}
+}
+
+func TestSolveLinearFreeSymbols(t *testing.T) {
+	p := Problem{
+		Cells:  map[int]string{0: "a", 1: "c"},
+		Ranges: map[string]Range{"a": {Min: 0, Max: 5}, "c": {Min: 0, Max: 1}},
+		Target: 7,
+	}
+
+	// c isn't part of the expression so it can be anything.
+	expr := Symbol("a").Multiply(Constant(2)).Add(Constant(1))
+
+	want := []Solution{{"a": 3, "c": 0}, {"a": 3, "c": 1}}
+	if got := p.solveLinear(expr); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// a would have to be 6, outside of its range.
+	p.Target = 13
+	if got := p.solveLinear(expr); len(got) != 0 {
+		t.Errorf("got %v, want no solutions", got)
+	}
+}
+
+func TestSolveNotHalting(t *testing.T) {
+	p := Problem{
+		Sequence: []int{1105, 1, 0, 7},
+		Cells:    map[int]string{3: "a"},
+		Ranges:   map[string]Range{"a": {Min: 0, Max: 1}},
+		Target:   1,
+	}
+
+	done := make(chan []Solution)
+
+	go func() {
+		solutions, _, err := Solve(p)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		done <- solutions
+	}()
+
+	select {
+	case solutions := <-done:
+		if len(solutions) != 0 {
+			t.Errorf("got %v, want no solutions for a program never halting", solutions)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("solving a program never halting didn't stop")
+	}
+}
+
+func TestSolveMissingRange(t *testing.T) {
+	p := Problem{
+		Sequence: []int{1, 0, 0, 0, 99},
+		Cells:    map[int]string{1: "a"},
+		Target:   1,
+	}
+
+	if _, _, err := Solve(p); err == nil {
+		t.Error("expected an error for a symbol without a range")
+	}
+}
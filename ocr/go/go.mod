@@ -0,0 +1,3 @@
+module advent.of.code/ocr
+
+go 1.13
@@ -0,0 +1,223 @@
+// Package ocr recognizes the capital letters Advent of Code renders as pixel
+// art, both in the small 4x6 font and the large 6x10 font.
+package ocr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unknown is used for every letter not found in the font.
+const Unknown = '?'
+
+// Each letter is drawn with `#` for lit cells and `.` for dark cells, one
+// string per row.
+// nolint: gochecknoglobals
+var smallFont = map[rune][]string{
+	'A': {".##.", "#..#", "#..#", "####", "#..#", "#..#"},
+	'B': {"###.", "#..#", "###.", "#..#", "#..#", "###."},
+	'C': {".##.", "#..#", "#...", "#...", "#..#", ".##."},
+	'E': {"####", "#...", "###.", "#...", "#...", "####"},
+	'F': {"####", "#...", "###.", "#...", "#...", "#..."},
+	'G': {".##.", "#..#", "#...", "#.##", "#..#", ".###"},
+	'H': {"#..#", "#..#", "####", "#..#", "#..#", "#..#"},
+	'I': {".###", "..#.", "..#.", "..#.", "..#.", ".###"},
+	'J': {"..##", "...#", "...#", "...#", "#..#", ".##."},
+	'K': {"#..#", "#.#.", "##..", "#.#.", "#.#.", "#..#"},
+	'L': {"#...", "#...", "#...", "#...", "#...", "####"},
+	'O': {".##.", "#..#", "#..#", "#..#", "#..#", ".##."},
+	'P': {"###.", "#..#", "#..#", "###.", "#...", "#..."},
+	'R': {"###.", "#..#", "#..#", "###.", "#.#.", "#..#"},
+	'S': {".###", "#...", "#...", ".##.", "...#", "###."},
+	'U': {"#..#", "#..#", "#..#", "#..#", "#..#", ".##."},
+	'Y': {"#...#", "#...#", ".#.#.", "..#..", "..#..", "..#.."},
+	'Z': {"####", "...#", "..#.", ".#..", "#...", "####"},
+}
+
+// nolint: gochecknoglobals
+var largeFont = map[rune][]string{
+	'A': {"..##..", ".#..#.", "#....#", "#....#", "#....#", "######", "#....#", "#....#", "#....#", "#....#"},
+	'B': {"#####.", "#....#", "#....#", "#....#", "#####.", "#....#", "#....#", "#....#", "#....#", "#####."},
+	'C': {".####.", "#....#", "#.....", "#.....", "#.....", "#.....", "#.....", "#.....", "#....#", ".####."},
+	'E': {"######", "#.....", "#.....", "#.....", "#####.", "#.....", "#.....", "#.....", "#.....", "######"},
+	'F': {"######", "#.....", "#.....", "#.....", "#####.", "#.....", "#.....", "#.....", "#.....", "#....."},
+	'G': {".####.", "#....#", "#.....", "#.....", "#.....", "#..###", "#....#", "#....#", "#...##", ".###.#"},
+	'H': {"#....#", "#....#", "#....#", "#....#", "######", "#....#", "#....#", "#....#", "#....#", "#....#"},
+	'J': {"...###", "....#.", "....#.", "....#.", "....#.", "....#.", "....#.", "#...#.", "#...#.", ".###.."},
+	'K': {"#....#", "#...#.", "#..#..", "#.#...", "##....", "##....", "#.#...", "#..#..", "#...#.", "#....#"},
+	'L': {"#.....", "#.....", "#.....", "#.....", "#.....", "#.....", "#.....", "#.....", "#.....", "######"},
+	'N': {"#....#", "##...#", "##...#", "#.#..#", "#.#..#", "#..#.#", "#..#.#", "#...##", "#...##", "#....#"},
+	'P': {"#####.", "#....#", "#....#", "#....#", "#####.", "#.....", "#.....", "#.....", "#.....", "#....."},
+	'R': {"#####.", "#....#", "#....#", "#....#", "#####.", "#..#..", "#...#.", "#...#.", "#....#", "#....#"},
+	'X': {"#....#", "#....#", ".#..#.", ".#..#.", "..##..", "..##..", ".#..#.", ".#..#.", "#....#", "#....#"},
+	'Z': {"######", ".....#", ".....#", "....#.", "...#..", "..#...", ".#....", "#.....", "#.....", "######"},
+}
+
+// nolint: gochecknoglobals
+var fonts = map[int]map[string]rune{
+	6:  index(smallFont),
+	10: index(largeFont),
+}
+
+// UnknownLetterError is returned when one or more letters isn't found in the
+// font. Text holds the letters read with Unknown in place of the missing ones.
+type UnknownLetterError struct {
+	Text string
+}
+
+func (e *UnknownLetterError) Error() string {
+	return fmt.Sprintf("unknown letters in '%s'", e.Text)
+}
+
+// Recognize reads the letters in a grid of lit cells. Rows and columns without
+// any lit cells around the text are ignored and letters are separated by
+// columns without any lit cells. The font is chosen by the height of the text.
+func Recognize(lit [][]bool) (string, error) {
+	lit = trimRows(lit)
+
+	if len(lit) == 0 {
+		return "", nil
+	}
+
+	font, ok := fonts[len(lit)]
+	if !ok {
+		return "", fmt.Errorf("no font with height %d", len(lit))
+	}
+
+	var (
+		text    strings.Builder
+		unknown = false
+	)
+
+	for _, glyph := range letters(lit) {
+		letter, ok := font[glyph]
+		if !ok {
+			letter = Unknown
+			unknown = true
+		}
+
+		text.WriteRune(letter)
+	}
+
+	if unknown {
+		return text.String(), &UnknownLetterError{Text: text.String()}
+	}
+
+	return text.String(), nil
+}
+
+// FromStrings creates a grid of lit cells from rows of text, where every
+// character equal to on is lit.
+func FromStrings(rows []string, on rune) [][]bool {
+	lit := make([][]bool, len(rows))
+
+	for i, row := range rows {
+		for _, c := range row {
+			lit[i] = append(lit[i], c == on)
+		}
+	}
+
+	return lit
+}
+
+// letters splits the grid into letters at every column without lit cells and
+// returns each letter in the same format as the font.
+func letters(lit [][]bool) []string {
+	var (
+		width   = 0
+		glyphs  = []string{}
+		current = make([]strings.Builder, len(lit))
+		columns = 0
+	)
+
+	for _, row := range lit {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	isLit := func(row, col int) bool {
+		return col < len(lit[row]) && lit[row][col]
+	}
+
+	flush := func() {
+		if columns == 0 {
+			return
+		}
+
+		rows := make([]string, len(current))
+		for i := range current {
+			rows[i] = current[i].String()
+			current[i].Reset()
+		}
+
+		glyphs = append(glyphs, strings.Join(rows, "\n"))
+		columns = 0
+	}
+
+	for col := 0; col < width; col++ {
+		empty := true
+
+		for row := range lit {
+			if isLit(row, col) {
+				empty = false
+				break
+			}
+		}
+
+		if empty {
+			flush()
+			continue
+		}
+
+		for row := range lit {
+			if isLit(row, col) {
+				current[row].WriteRune('#')
+			} else {
+				current[row].WriteRune('.')
+			}
+		}
+
+		columns++
+	}
+
+	flush()
+
+	return glyphs
+}
+
+func trimRows(lit [][]bool) [][]bool {
+	empty := func(row []bool) bool {
+		for _, v := range row {
+			if v {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for len(lit) > 0 && empty(lit[0]) {
+		lit = lit[1:]
+	}
+
+	for len(lit) > 0 && empty(lit[len(lit)-1]) {
+		lit = lit[:len(lit)-1]
+	}
+
+	return lit
+}
+
+// index creates a lookup from each letter, with the empty columns on each side
+// removed, to the letter it represents.
+func index(font map[rune][]string) map[string]rune {
+	lookup := map[string]rune{}
+
+	for letter, rows := range font {
+		for _, glyph := range letters(FromStrings(rows, '#')) {
+			lookup[glyph] = letter
+		}
+	}
+
+	return lookup
+}
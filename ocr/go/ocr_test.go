@@ -0,0 +1,91 @@
+package ocr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRecognize(t *testing.T) {
+	cases := []struct {
+		name string
+		rows []string
+		want string
+	}{
+		{
+			name: "day 08",
+			rows: []string{
+				".##...##..#..#.###..####.",
+				"#..#.#..#.#.#..#..#....#.",
+				"#..#.#....##...#..#...#..",
+				"####.#....#.#..###...#...",
+				"#..#.#..#.#.#..#....#....",
+				"#..#..##..#..#.#....####.",
+			},
+			want: "ACKPZ",
+		},
+		{
+			name: "day 11",
+			rows: []string{
+				".####.###..####.###..#..#.####.####.###....",
+				"....#.#..#....#.#..#.#.#..#.......#.#..#...",
+				"...#..#..#...#..#..#.##...###....#..#..#...",
+				"..#...###...#...###..#.#..#.....#...###....",
+				".#....#.#..#....#....#.#..#....#....#.#....",
+				".####.#..#.####.#....#..#.####.####.#..#...",
+			},
+			want: "ZRZPKEZR",
+		},
+		{
+			name: "large font",
+			rows: []string{
+				"........................",
+				".#....#.....###..#....#.",
+				".##...#......#...#....#.",
+				".##...#......#....#..#..",
+				".#.#..#......#....#..#..",
+				".#.#..#......#.....##...",
+				".#..#.#......#.....##...",
+				".#..#.#......#....#..#..",
+				".#...##..#...#....#..#..",
+				".#...##..#...#...#....#.",
+				".#....#...###....#....#.",
+				"........................",
+			},
+			want: "NJX",
+		},
+	}
+
+	for _, tc := range cases {
+		text, err := Recognize(FromStrings(tc.rows, '#'))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+
+		if text != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, text, tc.want)
+		}
+	}
+}
+
+func TestRecognizeUnknownLetter(t *testing.T) {
+	rows := []string{
+		"#..#.####",
+		"#..#.#..#",
+		"####.#..#",
+		"#..#.#..#",
+		"#..#.#..#",
+		"#..#.####",
+	}
+
+	text, err := Recognize(FromStrings(rows, '#'))
+
+	var unknown *UnknownLetterError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("got error %v, want an UnknownLetterError", err)
+	}
+
+	if text != "H?" || unknown.Text != "H?" {
+		t.Errorf("got text %s and %s in the error, want H?", text, unknown.Text)
+	}
+}
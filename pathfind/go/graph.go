@@ -0,0 +1,70 @@
+// Package pathfind implements breadth-first search, Dijkstra and A* over
+// explicit graphs and sparse grids.
+package pathfind
+
+import (
+	"advent.of.code/grid"
+)
+
+// Node is a node in a graph. It must be comparable since it's used as a map
+// key, e.g. a string or a grid.Point.
+type Node interface{}
+
+// Edge represents a move to another node with the given cost.
+type Edge struct {
+	To   Node
+	Cost int
+}
+
+// Graph is anything where the edges from a node can be listed.
+type Graph interface {
+	Edges(from Node) []Edge
+}
+
+// Explicit is a graph with every edge stored.
+type Explicit map[Node][]Edge
+
+// Grid is a graph of the points on a grid where every step to a passable
+// neighbour costs one. Passable must bound the grid since searches expand
+// every point reachable; no point is passable if it isn't set.
+type Grid struct {
+	Passable func(p grid.Point) bool
+}
+
+// Edges returns the edges stored from the node.
+func (g Explicit) Edges(from Node) []Edge {
+	return g[from]
+}
+
+// Add adds an edge from a to b.
+func (g Explicit) Add(a, b Node, cost int) {
+	g[a] = append(g[a], Edge{To: b, Cost: cost})
+}
+
+// AddUndirected adds an edge from a to b and an edge from b to a.
+func (g Explicit) AddUndirected(a, b Node, cost int) {
+	g.Add(a, b, cost)
+	g.Add(b, a, cost)
+}
+
+// Edges returns an edge to every passable neighbour of the point.
+func (g Grid) Edges(from Node) []Edge {
+	var (
+		p     = from.(grid.Point)
+		edges = make([]Edge, 0, len(grid.Headings))
+	)
+
+	if g.Passable == nil {
+		return edges
+	}
+
+	for _, n := range p.Neighbours() {
+		if !g.Passable(n) {
+			continue
+		}
+
+		edges = append(edges, Edge{To: n, Cost: 1})
+	}
+
+	return edges
+}
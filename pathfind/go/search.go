@@ -0,0 +1,192 @@
+package pathfind
+
+import (
+	"container/heap"
+)
+
+// Result holds the distance to every node reached by a search and the node
+// each of them was reached from.
+type Result struct {
+	Dist map[Node]int
+	Prev map[Node]Node
+}
+
+// Path returns every node on the shortest path from a start node to the given
+// node, including both. Returns false if the node wasn't reached.
+func (r Result) Path(to Node) ([]Node, bool) {
+	if _, ok := r.Dist[to]; !ok {
+		return nil, false
+	}
+
+	path := []Node{to}
+
+	for {
+		prev, ok := r.Prev[to]
+		if !ok {
+			break
+		}
+
+		path = append(path, prev)
+		to = prev
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, true
+}
+
+// Max returns the largest distance to any node reached.
+func (r Result) Max() int {
+	maximum := 0
+
+	for _, d := range r.Dist {
+		if d > maximum {
+			maximum = d
+		}
+	}
+
+	return maximum
+}
+
+func newResult() Result {
+	return Result{
+		Dist: map[Node]int{},
+		Prev: map[Node]Node{},
+	}
+}
+
+// BFS searches the graph breadth first from every start node at once, counting
+// every edge as one step regardless of its cost.
+func BFS(g Graph, starts ...Node) Result {
+	var (
+		r     = newResult()
+		queue = []Node{}
+	)
+
+	for _, s := range starts {
+		if _, ok := r.Dist[s]; ok {
+			continue
+		}
+
+		r.Dist[s] = 0
+		queue = append(queue, s)
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, e := range g.Edges(current) {
+			if _, ok := r.Dist[e.To]; ok {
+				continue
+			}
+
+			r.Dist[e.To] = r.Dist[current] + 1
+			r.Prev[e.To] = current
+			queue = append(queue, e.To)
+		}
+	}
+
+	return r
+}
+
+// Dijkstra finds the cheapest distance from any of the start nodes to every
+// node reachable. Costs must not be negative.
+func Dijkstra(g Graph, starts ...Node) Result {
+	return search(g, nil, nil, starts...)
+}
+
+// AStar finds the cheapest path from start to goal, guided by the heuristic h
+// estimating the remaining cost from a node to the goal. The heuristic must be
+// consistent, never dropping more than the cost of an edge, for the path to be
+// the cheapest. The Manhattan distance is consistent on a grid. Returns the path
+// including both start and goal, its cost and false if goal can't be reached.
+func AStar(g Graph, start, goal Node, h func(Node) int) ([]Node, int, bool) {
+	r := search(g, &goal, h, start)
+
+	path, ok := r.Path(goal)
+	if !ok {
+		return nil, 0, false
+	}
+
+	return path, r.Dist[goal], true
+}
+
+// search runs Dijkstra from the start nodes, stopping at goal if set. The
+// heuristic, if set, is added to the distance when choosing the next node which
+// makes it A*.
+func search(g Graph, goal *Node, h func(Node) int, starts ...Node) Result {
+	var (
+		r    = newResult()
+		done = map[Node]struct{}{}
+		q    = &queue{}
+	)
+
+	estimate := func(n Node) int {
+		if h == nil {
+			return 0
+		}
+
+		return h(n)
+	}
+
+	for _, s := range starts {
+		r.Dist[s] = 0
+		heap.Push(q, item{node: s, priority: estimate(s)})
+	}
+
+	for q.Len() > 0 {
+		current := heap.Pop(q).(item).node
+
+		if _, ok := done[current]; ok {
+			continue
+		}
+
+		done[current] = struct{}{}
+
+		if goal != nil && current == *goal {
+			break
+		}
+
+		for _, e := range g.Edges(current) {
+			dist := r.Dist[current] + e.Cost
+
+			if d, ok := r.Dist[e.To]; ok && d <= dist {
+				continue
+			}
+
+			r.Dist[e.To] = dist
+			r.Prev[e.To] = current
+
+			heap.Push(q, item{node: e.To, priority: dist + estimate(e.To)})
+		}
+	}
+
+	return r
+}
+
+// item is a node in the priority queue. Nodes are pushed again when a cheaper
+// distance is found and the stale items are skipped when popped.
+type item struct {
+	node     Node
+	priority int
+}
+
+// queue is a priority queue of items with the lowest priority first,
+// implementing heap.Interface.
+type queue []item
+
+func (q queue) Len() int            { return len(q) }
+func (q queue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q queue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x interface{}) { *q = append(*q, x.(item)) }
+
+func (q *queue) Pop() interface{} {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return it
+}
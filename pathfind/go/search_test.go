@@ -0,0 +1,242 @@
+package pathfind
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"advent.of.code/grid"
+)
+
+// weighted returns a graph where the direct edge from a to b is more expensive
+// than going around through c and d, and e can't be reached.
+func weighted() Explicit {
+	g := Explicit{}
+
+	g.Add("a", "b", 10)
+	g.Add("a", "c", 1)
+	g.Add("c", "d", 1)
+	g.Add("d", "b", 1)
+	g.Add("b", "e", 1)
+	g.Add("f", "e", 1)
+
+	return g
+}
+
+// maze parses a grid where every point that's not # is passable.
+func maze(rows ...string) (Grid, map[rune]grid.Point) {
+	var (
+		open    = map[grid.Point]struct{}{}
+		special = map[rune]grid.Point{}
+	)
+
+	for y, row := range rows {
+		for x, c := range row {
+			p := grid.Point{X: x, Y: y}
+
+			if c == '#' {
+				continue
+			}
+
+			open[p] = struct{}{}
+
+			if c != '.' {
+				special[c] = p
+			}
+		}
+	}
+
+	return Grid{Passable: func(p grid.Point) bool {
+		_, ok := open[p]
+		return ok
+	}}, special
+}
+
+func TestWeightedGraph(t *testing.T) {
+	g := weighted()
+
+	bfs := BFS(g, "a")
+	if bfs.Dist["b"] != 1 {
+		t.Errorf("BFS: got distance %d to b, want 1", bfs.Dist["b"])
+	}
+
+	if path, _ := bfs.Path("b"); !reflect.DeepEqual(path, []Node{"a", "b"}) {
+		t.Errorf("BFS: got path %v to b, want [a b]", path)
+	}
+
+	dijkstra := Dijkstra(g, "a")
+	if dijkstra.Dist["b"] != 3 {
+		t.Errorf("Dijkstra: got distance %d to b, want 3", dijkstra.Dist["b"])
+	}
+
+	if path, _ := dijkstra.Path("b"); !reflect.DeepEqual(path, []Node{"a", "c", "d", "b"}) {
+		t.Errorf("Dijkstra: got path %v to b, want [a c d b]", path)
+	}
+
+	if dijkstra.Dist["e"] != 4 {
+		t.Errorf("Dijkstra: got distance %d to e, want 4", dijkstra.Dist["e"])
+	}
+
+	path, cost, ok := AStar(g, "a", "b", func(Node) int { return 0 })
+	if !ok || cost != 3 || !reflect.DeepEqual(path, []Node{"a", "c", "d", "b"}) {
+		t.Errorf("AStar: got path %v with cost %d (%t), want [a c d b] with cost 3", path, cost, ok)
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	g := weighted()
+
+	if _, ok := BFS(g, "a").Path("f"); ok {
+		t.Error("BFS: expected f to be unreachable")
+	}
+
+	if _, ok := Dijkstra(g, "a").Path("f"); ok {
+		t.Error("Dijkstra: expected f to be unreachable")
+	}
+
+	if _, _, ok := AStar(g, "a", "f", func(Node) int { return 0 }); ok {
+		t.Error("AStar: expected f to be unreachable")
+	}
+
+	walled, special := maze(
+		"S.#..",
+		"..#.G",
+	)
+
+	if _, _, ok := AStar(walled, special['S'], special['G'], func(n Node) int {
+		return grid.Manhattan(n.(grid.Point), special['G'])
+	}); ok {
+		t.Error("AStar: expected G to be unreachable behind the wall")
+	}
+}
+
+func TestPath(t *testing.T) {
+	g, special := maze(
+		"S.#",
+		"#.#",
+		"#.G",
+	)
+
+	want := []Node{
+		grid.Point{X: 0, Y: 0},
+		grid.Point{X: 1, Y: 0},
+		grid.Point{X: 1, Y: 1},
+		grid.Point{X: 1, Y: 2},
+		grid.Point{X: 2, Y: 2},
+	}
+
+	path, ok := BFS(g, special['S']).Path(special['G'])
+	if !ok || !reflect.DeepEqual(path, want) {
+		t.Errorf("got path %v (%t), want %v", path, ok, want)
+	}
+
+	path, ok = BFS(g, special['S']).Path(special['S'])
+	if !ok || !reflect.DeepEqual(path, []Node{special['S']}) {
+		t.Errorf("got path %v (%t) to the start, want only the start", path, ok)
+	}
+}
+
+func TestGridPassable(t *testing.T) {
+	g, special := maze(
+		"#######",
+		"#S....#",
+		"#####.#",
+		"#G....#",
+		"#######",
+	)
+
+	r := BFS(g, special['S'])
+
+	if d := r.Dist[special['G']]; d != 10 {
+		t.Errorf("got distance %d to G, want 10", d)
+	}
+
+	if len(r.Dist) != 11 {
+		t.Errorf("reached %d points, want 11", len(r.Dist))
+	}
+
+	if r.Max() != 10 {
+		t.Errorf("got max distance %d, want 10", r.Max())
+	}
+
+	if r := BFS(Grid{}, grid.Point{}); len(r.Dist) != 1 {
+		t.Errorf("reached %d points without Passable, want only the start", len(r.Dist))
+	}
+}
+
+func TestMultiSourceBFS(t *testing.T) {
+	g, special := maze(
+		"A.....B",
+	)
+
+	r := BFS(g, special['A'], special['B'])
+
+	for x, want := range []int{0, 1, 2, 3, 2, 1, 0} {
+		if d := r.Dist[grid.Point{X: x}]; d != want {
+			t.Errorf("got distance %d at %d, want %d", d, x, want)
+		}
+	}
+
+	if r.Max() != 3 {
+		t.Errorf("got max distance %d, want 3", r.Max())
+	}
+}
+
+// TestRandomPairs checks that Dijkstra and A* agree with BFS on the length of
+// the shortest path between random points in a random maze, where every step
+// costs one.
+func TestRandomPairs(t *testing.T) {
+	const size = 30
+
+	var (
+		rnd  = rand.New(rand.NewSource(1))
+		rows = make([]string, size)
+		open = []grid.Point{}
+	)
+
+	for y := range rows {
+		var b strings.Builder
+
+		for x := 0; x < size; x++ {
+			if rnd.Intn(10) < 3 {
+				b.WriteByte('#')
+				continue
+			}
+
+			b.WriteByte('.')
+
+			open = append(open, grid.Point{X: x, Y: y})
+		}
+
+		rows[y] = b.String()
+	}
+
+	g, _ := maze(rows...)
+
+	for i := 0; i < 200; i++ {
+		var (
+			from = open[rnd.Intn(len(open))]
+			to   = open[rnd.Intn(len(open))]
+		)
+
+		bfs, reachable := BFS(g, from).Dist[to]
+		dijkstra := Dijkstra(g, from).Dist[to]
+
+		path, cost, ok := AStar(g, from, to, func(n Node) int {
+			return grid.Manhattan(n.(grid.Point), to)
+		})
+
+		if ok != reachable {
+			t.Fatalf("%v to %v: AStar reached %t, BFS reached %t", from, to, ok, reachable)
+		}
+
+		if !reachable {
+			continue
+		}
+
+		if dijkstra != bfs || cost != bfs || len(path)-1 != bfs {
+			t.Fatalf("%v to %v: BFS %d, Dijkstra %d, AStar %d with %d steps", from, to, bfs, dijkstra, cost, len(path)-1)
+		}
+	}
+}